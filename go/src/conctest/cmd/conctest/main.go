@@ -0,0 +1,11 @@
+// * * ** *** ***** ******** ************* *********************
+// The conctest command
+// * * ** *** ***** ******** ************* *********************
+
+package main
+
+import "conctest"
+
+func main() {
+	conctest.Main()
+}
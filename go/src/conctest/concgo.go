@@ -0,0 +1,11877 @@
+// * * ** *** ***** ******** ************* *********************
+// Observing concurrent code execution on Go
+//                                                   (\(\
+//                                                  =(^.^)=
+// * * ** *** ***** ******** ************* *********************
+
+package conctest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"math"
+	"math/rand"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/metrics"
+	"runtime/pprof"
+	"runtime/trace"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Time
+
+// The moment the process started; time.Time keeps a monotonic clock
+// reading, which wall clock adjustments mid-run do not affect
+var CLOCK_ORIGIN = time.Now()
+
+// Moments are durations on the monotonic clock since the process started,
+// so that tasks shorter than a millisecond still get their own durations
+func now() time.Duration {
+	return time.Since(CLOCK_ORIGIN)
+}
+
+func since(initial_moment time.Duration) time.Duration {
+	return now() - initial_moment
+}
+
+// Reports give durations in milliseconds with fractions
+func to_ms(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
+
+// Options give durations in whole milliseconds
+func from_ms(ms int) time.Duration {
+	return time.Duration(ms) * time.Millisecond
+}
+
+// Drawing random numbers
+
+// Every random draw of a run, from workload inputs to arrivals and
+// launch order, comes from one seeded source, so a run repeated with the
+// seed of the report draws the same numbers; tasks running at the same
+// time still take them in the order they happen to ask
+type Random struct {
+	lock sync.Mutex
+	rng  *rand.Rand
+	seed int64
+}
+
+func create_random(seed int64) *Random {
+	return &Random{sync.Mutex{}, rand.New(rand.NewSource(seed)), seed}
+}
+
+var RANDOM = create_random(time.Now().UnixNano())
+
+func (r *Random) get_seed() int64 {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.seed
+}
+
+func (r *Random) reseed(seed int64) {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.rng = rand.New(rand.NewSource(seed))
+	r.seed = seed
+}
+
+func (r *Random) float64() float64 {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.rng.Float64()
+}
+
+func (r *Random) exp_float64() float64 {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.rng.ExpFloat64()
+}
+
+func (r *Random) uint64() uint64 {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.rng.Uint64()
+}
+
+func (r *Random) int63n(n int64) int64 {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.rng.Int63n(n)
+}
+
+func (r *Random) shuffle(n int, swap func(i, j int)) {
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.rng.Shuffle(n, swap)
+}
+
+// Spending time with fun
+
+type Triplet = [3]float64
+
+func random_item() float64 {
+	return RANDOM.float64()
+}
+
+func random_triplet() Triplet {
+	return Triplet{random_item(), random_item(), random_item()}
+}
+
+func get_next_triplet(triplet Triplet) Triplet {
+
+	applicant := triplet[0] + triplet[1] - triplet[2]
+
+	if math.Abs(applicant) <= 1.0 {
+		return Triplet{triplet[1], triplet[2], applicant}
+	} else {
+		return Triplet{triplet[1], triplet[2], 1.0 / applicant}
+	}
+}
+
+func approx_eq(f1, f2 float64) bool {
+	return math.Abs(f1-f2) < 1e-14
+}
+
+func is_convergent(triplet, next_triplet Triplet) bool {
+	return approx_eq(triplet[0], next_triplet[0]) &&
+		approx_eq(triplet[1], next_triplet[1]) &&
+		approx_eq(triplet[2], next_triplet[2])
+}
+
+// Cancellation
+
+const CANCEL_CHECK_CYCLES = 4096
+
+// A tight loop looks at the clock every so many steps and at the context
+// every interval, however long its cycles are
+const TIGHT_CHECK_STEPS = 1 << 16
+const TIGHT_CHECK_INTERVAL = time.Millisecond
+
+func is_cancelled(ctx context.Context, step int) bool {
+	return step%CANCEL_CHECK_CYCLES == CANCEL_CHECK_CYCLES-1 && ctx.Err() != nil
+}
+
+func is_cancellation(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+func iterate(ctx context.Context, initial_triplet Triplet, n_cycles int) float64 {
+
+	triplet := initial_triplet
+
+	prokukarek := false
+
+	for step := 0; step < n_cycles && !is_cancelled(ctx, step); step++ {
+
+		next_triplet := get_next_triplet(triplet)
+
+		if is_convergent(triplet, next_triplet) && !prokukarek {
+			print_convergency(initial_triplet, step, triplet[2])
+			prokukarek = true
+		}
+
+		triplet = next_triplet
+	}
+
+	return triplet[2]
+}
+
+func iterate_integer(ctx context.Context, seed uint64, n_cycles int) uint64 {
+
+	x := seed | 1
+
+	for step := 0; step < n_cycles && !is_cancelled(ctx, step); step++ {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+		x += x % 1000003
+	}
+
+	return x
+}
+
+// A cycle is a loop of loop_len steps without function calls, so only
+// asynchronous preemption can take the CPU away in the middle of it; the
+// loop breaks into runs of TIGHT_CHECK_STEPS to notice a cancelled context
+func iterate_tight(ctx context.Context, seed uint64, n_cycles, loop_len int) uint64 {
+
+	x := seed | 1
+	last_check := time.Now()
+	unchecked := 0
+
+	for cycle := 0; cycle < n_cycles; cycle++ {
+		for step := 0; step < loop_len; {
+
+			run_end := min(loop_len, step+TIGHT_CHECK_STEPS-unchecked)
+			unchecked += run_end - step
+
+			for ; step < run_end; step++ {
+				x ^= x << 13
+				x ^= x >> 7
+				x ^= x << 17
+			}
+
+			if unchecked < TIGHT_CHECK_STEPS {
+				continue
+			}
+
+			unchecked = 0
+
+			if time.Since(last_check) >= TIGHT_CHECK_INTERVAL {
+				if ctx.Err() != nil {
+					return x
+				}
+				last_check = time.Now()
+			}
+		}
+	}
+
+	return x
+}
+
+func block_go(ctx context.Context, n_cycles int) {
+
+	timer := time.NewTimer(time.Duration(n_cycles) * time.Microsecond)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+const FSYNC_RECORD_SIZE = 64
+
+func append_fsync(ctx context.Context, dir string, n_cycles int) error {
+
+	out_file, err := os.CreateTemp(dir, "conctest-fsync-*")
+
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(out_file.Name())
+	defer out_file.Close()
+
+	record := make([]byte, FSYNC_RECORD_SIZE)
+
+	for step := 0; step < n_cycles && ctx.Err() == nil; step++ {
+		if _, err := out_file.Write(record); err != nil {
+			return err
+		}
+		if err := out_file.Sync(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func run_pipeline(ctx context.Context, n_items, n_stages, buffer_size, stage_cycles int) []time.Duration {
+
+	stage_times := make([]time.Duration, n_stages)
+
+	source := make(chan uint64, buffer_size)
+	input := source
+
+	var syncler sync.WaitGroup
+
+	for stage_idx := 0; stage_idx < n_stages; stage_idx++ {
+
+		output := make(chan uint64, buffer_size)
+
+		syncler.Add(1)
+
+		go func(_stage_idx int, _input <-chan uint64, _output chan<- uint64) {
+
+			var busy time.Duration
+
+			for item := range _input {
+				start := time.Now()
+				item = iterate_integer(ctx, item, stage_cycles)
+				busy += time.Since(start)
+				_output <- item
+			}
+
+			close(_output)
+			stage_times[_stage_idx] = busy
+			syncler.Done()
+		}(stage_idx, input, output)
+
+		input = output
+	}
+
+	go func() {
+		for item := 0; item < n_items && ctx.Err() == nil; item++ {
+			source <- uint64(item)
+		}
+		close(source)
+	}()
+
+	for range input {
+	}
+
+	syncler.Wait()
+
+	return stage_times
+}
+
+type SharedMap interface {
+	load(key uint64) (uint64, bool)
+	store(key, value uint64)
+}
+
+type SyncMap struct {
+	entries sync.Map
+}
+
+func (m *SyncMap) load(key uint64) (uint64, bool) {
+	value, ok := m.entries.Load(key)
+	if ok {
+		return value.(uint64), true
+	} else {
+		return 0, false
+	}
+}
+
+func (m *SyncMap) store(key, value uint64) {
+	m.entries.Store(key, value)
+}
+
+type LockedMap struct {
+	lock    sync.RWMutex
+	entries map[uint64]uint64
+}
+
+func (m *LockedMap) load(key uint64) (uint64, bool) {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	value, ok := m.entries[key]
+	return value, ok
+}
+
+func (m *LockedMap) store(key, value uint64) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.entries[key] = value
+}
+
+func create_locked_map() *LockedMap {
+	return &LockedMap{entries: map[uint64]uint64{}}
+}
+
+const MAP_SHARDS = 64
+
+type ShardedMap struct {
+	shards []*LockedMap
+}
+
+func (m *ShardedMap) get_shard(key uint64) *LockedMap {
+	return m.shards[key%uint64(len(m.shards))]
+}
+
+func (m *ShardedMap) load(key uint64) (uint64, bool) {
+	return m.get_shard(key).load(key)
+}
+
+func (m *ShardedMap) store(key, value uint64) {
+	m.get_shard(key).store(key, value)
+}
+
+func create_sharded_map(n_shards int) *ShardedMap {
+
+	m := &ShardedMap{[]*LockedMap{}}
+
+	for shard_idx := 0; shard_idx < n_shards; shard_idx++ {
+		m.shards = append(m.shards, create_locked_map())
+	}
+
+	return m
+}
+
+func access_map(
+	ctx context.Context,
+	shared_map SharedMap,
+	seed uint64,
+	n_cycles, n_keys int,
+	write_ratio float64) uint64 {
+
+	x := seed | 1
+	var sum uint64 = 0
+
+	for step := 0; step < n_cycles && !is_cancelled(ctx, step); step++ {
+
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+
+		key := x % uint64(n_keys)
+
+		if float64((x>>32)%1000) < write_ratio*1000 {
+			shared_map.store(key, x)
+		} else {
+			value, _ := shared_map.load(key)
+			sum += value
+		}
+	}
+
+	return sum
+}
+
+// Choosing a workload
+
+var FIXED_TRIPLET = Triplet{0.25, 0.5, 0.75}
+
+const FIXED_SEED uint64 = 0x9e3779b97f4a7c15
+
+type WorkloadKind = int
+
+const (
+	WL_Float = iota
+	WL_Integer
+	WL_GoSleep
+	WL_CgoSleep
+	WL_Fsync
+	WL_Pipeline
+	WL_MapSync
+	WL_MapRWMutex
+	WL_MapSharded
+	WL_Tight
+)
+
+type WorkloadParams struct {
+	fsync_dir    string
+	n_stages     int
+	buffer_size  int
+	stage_cycles int
+	n_keys       int
+	write_ratio  float64
+	tight_len    int
+}
+
+func (wp WorkloadParams) get_fsync_dir() string {
+	return wp.fsync_dir
+}
+
+func (wp WorkloadParams) get_n_stages() int {
+	return wp.n_stages
+}
+
+func (wp WorkloadParams) get_buffer_size() int {
+	return wp.buffer_size
+}
+
+func (wp WorkloadParams) get_stage_cycles() int {
+	return wp.stage_cycles
+}
+
+func (wp WorkloadParams) get_n_keys() int {
+	return wp.n_keys
+}
+
+func (wp WorkloadParams) get_write_ratio() float64 {
+	return wp.write_ratio
+}
+
+func (wp WorkloadParams) get_tight_len() int {
+	return wp.tight_len
+}
+
+func create_workload_params(
+	fsync_dir string,
+	n_stages, buffer_size, stage_cycles, n_keys int,
+	write_ratio float64,
+	tight_len int) WorkloadParams {
+
+	return WorkloadParams{fsync_dir, n_stages, buffer_size, stage_cycles, n_keys, write_ratio, tight_len}
+}
+
+type Workload struct {
+	kind        WorkloadKind
+	fixed_start bool
+	params      WorkloadParams
+	shared_map  SharedMap
+}
+
+func (w Workload) get_kind() WorkloadKind {
+	return w.kind
+}
+
+func (w Workload) get_name() string {
+	switch w.get_kind() {
+	case WL_Integer:
+		return "integer"
+	case WL_GoSleep:
+		return "go-sleep"
+	case WL_CgoSleep:
+		return "cgo-sleep"
+	case WL_Fsync:
+		return "fsync"
+	case WL_Pipeline:
+		return "pipeline"
+	case WL_MapSync:
+		return "map-sync"
+	case WL_MapRWMutex:
+		return "map-rwmutex"
+	case WL_MapSharded:
+		return "map-sharded"
+	case WL_Tight:
+		return "tight"
+	default:
+		return "float"
+	}
+}
+
+func (w Workload) is_fixed_start() bool {
+	return w.fixed_start
+}
+
+func (w Workload) get_params() WorkloadParams {
+	return w.params
+}
+
+func (w Workload) get_initial_triplet() Triplet {
+	if w.is_fixed_start() {
+		return FIXED_TRIPLET
+	} else {
+		return random_triplet()
+	}
+}
+
+func (w Workload) get_seed() uint64 {
+	if w.is_fixed_start() {
+		return FIXED_SEED
+	} else {
+		return RANDOM.uint64()
+	}
+}
+
+// A C call cannot be interrupted, so cgo-sleep notices cancellation only after it returns
+func (w Workload) run(ctx context.Context, n_cycles int) ([]time.Duration, error) {
+
+	params := w.get_params()
+
+	var stage_times []time.Duration = nil
+	var err error = nil
+
+	switch w.get_kind() {
+	case WL_Integer:
+		iterate_integer(ctx, w.get_seed(), n_cycles)
+	case WL_Tight:
+		iterate_tight(ctx, w.get_seed(), n_cycles, params.get_tight_len())
+	case WL_GoSleep:
+		block_go(ctx, n_cycles)
+	case WL_CgoSleep:
+		block_cgo(n_cycles)
+	case WL_Fsync:
+		err = append_fsync(ctx, params.get_fsync_dir(), n_cycles)
+	case WL_Pipeline:
+		stage_times = run_pipeline(
+			ctx,
+			n_cycles,
+			params.get_n_stages(),
+			params.get_buffer_size(),
+			params.get_stage_cycles())
+	case WL_MapSync, WL_MapRWMutex, WL_MapSharded:
+		access_map(
+			ctx,
+			w.shared_map,
+			w.get_seed(),
+			n_cycles,
+			params.get_n_keys(),
+			params.get_write_ratio())
+	default:
+		iterate(ctx, w.get_initial_triplet(), n_cycles)
+	}
+
+	if err != nil {
+		return stage_times, err
+	} else {
+		return stage_times, ctx.Err()
+	}
+}
+
+// Shares fresh state, if any, between the tasks of one observation
+func (w Workload) prepare() Workload {
+
+	switch w.get_kind() {
+	case WL_MapSync:
+		w.shared_map = &SyncMap{}
+	case WL_MapRWMutex:
+		w.shared_map = create_locked_map()
+	case WL_MapSharded:
+		w.shared_map = create_sharded_map(MAP_SHARDS)
+	}
+
+	return w
+}
+
+func create_workload(kind WorkloadKind, fixed_start bool, params WorkloadParams) Workload {
+	return Workload{kind, fixed_start, params, nil}
+}
+
+// Names parse_workload_kinds accepts, offered by shell completion
+var WORKLOAD_NAMES = []string{
+	"float", "integer", "both", "go-sleep", "cgo-sleep", "sleep", "fsync", "pipeline",
+	"map-sync", "map-rwmutex", "map-sharded", "map", "tight",
+}
+
+func parse_workload_kinds(s string) ([]WorkloadKind, bool) {
+	switch s {
+	case "float":
+		return []WorkloadKind{WL_Float}, true
+	case "integer":
+		return []WorkloadKind{WL_Integer}, true
+	case "both":
+		return []WorkloadKind{WL_Float, WL_Integer}, true
+	case "go-sleep":
+		return []WorkloadKind{WL_GoSleep}, true
+	case "cgo-sleep":
+		return []WorkloadKind{WL_CgoSleep}, CGO_AVAILABLE
+	case "sleep":
+		return []WorkloadKind{WL_GoSleep, WL_CgoSleep}, CGO_AVAILABLE
+	case "fsync":
+		return []WorkloadKind{WL_Fsync}, true
+	case "pipeline":
+		return []WorkloadKind{WL_Pipeline}, true
+	case "map-sync":
+		return []WorkloadKind{WL_MapSync}, true
+	case "map-rwmutex":
+		return []WorkloadKind{WL_MapRWMutex}, true
+	case "map-sharded":
+		return []WorkloadKind{WL_MapSharded}, true
+	case "map":
+		return []WorkloadKind{WL_MapSync, WL_MapRWMutex, WL_MapSharded}, true
+	case "tight":
+		return []WorkloadKind{WL_Tight}, true
+	default:
+		return []WorkloadKind{}, false
+	}
+}
+
+func standard_task(ctx context.Context, workload Workload, task_idx, n_cycles int, launched time.Duration) Task {
+
+	start := now()
+	stage_times, err := workload.run(ctx, n_cycles)
+
+	task := create_task(task_idx, n_cycles, start, since(start), stage_times)
+	task.set_launched(launched)
+
+	if is_cancellation(err) {
+		task.set_status(TS_Cancelled)
+	} else if err != nil {
+		task.set_status(TS_Failed)
+		task.set_err(err)
+	}
+
+	return task
+}
+
+// Runs the task in a child process started as "t 1 <n_cycles> <options>",
+// which reports back how long after its own start the task started and how
+// long it took; monotonic clocks of different processes do not compare, so
+// the start counts from the moment the child was spawned
+func process_task(
+	ctx context.Context,
+	workload Workload,
+	child_options []string,
+	task_idx, n_cycles int,
+	launched time.Duration) Task {
+
+	task := create_task(task_idx, n_cycles, launched, 0, nil)
+	task.set_launched(launched)
+
+	self, err := os.Executable()
+
+	if err == nil {
+		child_args := append([]string{"t", "--cycles", strconv.Itoa(n_cycles)}, child_options...)
+		child_args = append(child_args, "--workload", workload.get_name())
+
+		var out []byte
+		cmd := exec.CommandContext(ctx, self, child_args...)
+		spawned := now()
+		out, err = cmd.Output()
+
+		if err == nil {
+			var start_offset, duration time.Duration
+			_, err = fmt.Sscanf(string(out), "%d %d", &start_offset, &duration)
+			task = create_task(task_idx, n_cycles, spawned+start_offset, duration, nil)
+			task.set_launched(launched)
+			// The child's CPU time, its start-up included
+			task.set_cpu_time(cmd.ProcessState.UserTime(), cmd.ProcessState.SystemTime())
+		}
+	}
+
+	if ctx.Err() != nil {
+		task.set_status(TS_Cancelled)
+	} else if err != nil {
+		task.set_status(TS_Failed)
+		task.set_err(err)
+	}
+
+	return task
+}
+
+// Serves process_task in the child process
+func run_child_task(ctx context.Context, workload Workload, n_cycles int) error {
+
+	start := now()
+
+	if _, err := workload.prepare().run(ctx, n_cycles); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "%d %d\n", start, since(start))
+
+	return nil
+}
+
+// Repeats the task until ctx_bound expires, counting completed runs;
+// the run cut off by the bound does not count
+func bounded_task(
+	ctx, ctx_bound context.Context,
+	workload Workload,
+	task_idx, n_cycles int,
+	launched time.Duration) Task {
+
+	start := now()
+	n_runs := 0
+
+	var err error = nil
+
+	for ctx_bound.Err() == nil && err == nil {
+		if _, err = workload.run(ctx_bound, n_cycles); err == nil {
+			n_runs++
+		}
+	}
+
+	task := create_task(task_idx, n_runs*n_cycles, start, since(start), nil)
+	task.set_launched(launched)
+	task.set_n_runs(n_runs)
+
+	if ctx.Err() != nil {
+		task.set_status(TS_Cancelled)
+	} else if err != nil && !is_cancellation(err) {
+		task.set_status(TS_Failed)
+		task.set_err(err)
+	}
+
+	return task
+}
+
+// Sizing tasks
+
+type Distribution = int
+
+const (
+	DIST_Fixed = iota
+	DIST_Uniform
+	DIST_Exponential
+)
+
+type TaskSizing struct {
+	distribution Distribution
+	spread       float64
+}
+
+func (ts TaskSizing) get_distribution() Distribution {
+	return ts.distribution
+}
+
+func (ts TaskSizing) get_spread() float64 {
+	return ts.spread
+}
+
+func (ts TaskSizing) draw_n_cycles(n_cycles int) int {
+
+	var drawn float64
+
+	switch ts.get_distribution() {
+	case DIST_Uniform:
+		drawn = float64(n_cycles) * (1 + ts.get_spread()*(2*RANDOM.float64()-1))
+	case DIST_Exponential:
+		drawn = float64(n_cycles) * RANDOM.exp_float64()
+	default:
+		drawn = float64(n_cycles)
+	}
+
+	if drawn < 1 {
+		return 1
+	} else {
+		return int(drawn)
+	}
+}
+
+func create_task_sizing(distribution Distribution, spread float64) TaskSizing {
+	return TaskSizing{distribution, spread}
+}
+
+func parse_distribution(s string) (Distribution, bool) {
+	switch s {
+	case "fixed":
+		return DIST_Fixed, true
+	case "uniform":
+		return DIST_Uniform, true
+	case "exponential":
+		return DIST_Exponential, true
+	default:
+		return DIST_Fixed, false
+	}
+}
+
+// Managing observation outcomes
+
+type TaskStatus = int
+
+const (
+	TS_Pending = iota
+	TS_Done
+	TS_Cancelled
+	TS_Failed
+	TS_TimedOut
+)
+
+func format_task_status(status TaskStatus) string {
+	switch status {
+	case TS_Done:
+		return "done"
+	case TS_Cancelled:
+		return "cancelled"
+	case TS_Failed:
+		return "failed"
+	case TS_TimedOut:
+		return "timed out"
+	default:
+		return "pending"
+	}
+}
+
+type Task struct {
+	idx           int
+	n_cycles      int
+	start         time.Duration
+	duration      time.Duration
+	stage_times   []time.Duration
+	status        TaskStatus
+	err           error
+	launched      time.Duration
+	n_runs        int
+	sched_latency time.Duration
+	series_idx    int
+	user_time     time.Duration
+	system_time   time.Duration
+	alloc_bytes   int64
+	n_allocs      int64
+}
+
+func (t Task) get_idx() int {
+	return t.idx
+}
+
+func (t Task) get_n_cycles() int {
+	return t.n_cycles
+}
+
+func (t Task) get_start() time.Duration {
+	return t.start
+}
+
+func (t *Task) recalc_start_relative(initial_moment time.Duration) {
+	t.start = t.start - initial_moment
+	t.launched = t.launched - initial_moment
+}
+
+func (t Task) get_finish() time.Duration {
+	return t.start + t.duration
+}
+
+func (t Task) get_duration() time.Duration {
+	return t.duration
+}
+
+func (t Task) get_stage_times() []time.Duration {
+	return t.stage_times
+}
+
+func (t Task) get_status() TaskStatus {
+	return t.status
+}
+
+func (t *Task) set_status(status TaskStatus) {
+	t.status = status
+}
+
+func (t Task) get_err() error {
+	return t.err
+}
+
+func (t *Task) set_err(err error) {
+	t.err = err
+}
+
+func (t Task) get_launched() time.Duration {
+	return t.launched
+}
+
+func (t *Task) set_launched(launched time.Duration) {
+	t.launched = launched
+}
+
+func (t Task) get_n_runs() int {
+	return t.n_runs
+}
+
+func (t *Task) set_n_runs(n_runs int) {
+	t.n_runs = n_runs
+}
+
+// From launching the task, with a go statement or a send to a queue,
+// to the first instruction of the task
+func (t Task) get_sched_latency() time.Duration {
+	return t.sched_latency
+}
+
+func (t *Task) set_sched_latency(sched_latency time.Duration) {
+	t.sched_latency = sched_latency
+}
+
+// -1 for tasks launched by an executor without series
+func (t Task) get_series_idx() int {
+	return t.series_idx
+}
+
+func (t *Task) set_series_idx(series_idx int) {
+	t.series_idx = series_idx
+}
+
+// CPU time is measured on request, -1 for tasks without it
+func (t Task) is_cpu_timed() bool {
+	return t.user_time >= 0
+}
+
+func (t Task) get_user_time() time.Duration {
+	return t.user_time
+}
+
+func (t Task) get_system_time() time.Duration {
+	return t.system_time
+}
+
+func (t Task) get_cpu_time() time.Duration {
+	return t.user_time + t.system_time
+}
+
+func (t *Task) set_cpu_time(user_time, system_time time.Duration) {
+	t.user_time = user_time
+	t.system_time = system_time
+}
+
+// Allocations are counted on request, -1 for tasks without them
+func (t Task) are_allocs_counted() bool {
+	return t.n_allocs >= 0
+}
+
+func (t Task) get_alloc_bytes() int64 {
+	return t.alloc_bytes
+}
+
+func (t Task) count_allocs() int64 {
+	return t.n_allocs
+}
+
+func (t *Task) set_allocs(alloc_bytes, n_allocs int64) {
+	t.alloc_bytes = alloc_bytes
+	t.n_allocs = n_allocs
+}
+
+// From launching the task to its finish
+func (t Task) get_latency() time.Duration {
+	return t.get_finish() - t.launched
+}
+
+func (t Task) get_queue_wait() time.Duration {
+	return t.start - t.launched
+}
+
+func (t Task) is_pending() bool {
+	return t.get_status() == TS_Pending
+}
+
+// Only a task done tells how long a task takes: a failed, cancelled, or
+// timed out one stopped partway, and a pending one never ran
+func (t Task) is_measured() bool {
+	return t.get_status() == TS_Done
+}
+
+func create_task(idx, n_cycles int, start time.Duration, duration time.Duration, stage_times []time.Duration) Task {
+	return Task{idx, n_cycles, start, duration, stage_times, TS_Done, nil, 0, 1, 0, -1, -1, -1, -1, -1}
+}
+
+// Accumulating task statistics online
+
+// Statistics of an observation that does not keep its tasks; durations
+// are accumulated with Welford's algorithm, of tasks done only
+type TaskStats struct {
+	lock             *sync.Mutex
+	n_tasks          int
+	n_registered     int
+	n_by_status      map[TaskStatus]int
+	n_measured       int
+	mean             float64
+	m2               float64
+	sum_duration     time.Duration
+	sum_measured     time.Duration
+	min_duration     time.Duration
+	max_duration     time.Duration
+	earliest_start   time.Duration
+	latest_finish    time.Duration
+	earliest_launch  time.Duration
+	latest_launch    time.Duration
+	nominal_cycles   int
+	completed_cycles int
+	completed_runs   int
+	done_runs        int
+	n_cpu_timed      int
+	cpu_timed_wall   time.Duration
+	sum_user_time    time.Duration
+	sum_system_time  time.Duration
+}
+
+// Safe to call from concurrent tasks
+func (s *TaskStats) add(task Task) {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.n_registered == 0 || task.get_start() < s.earliest_start {
+		s.earliest_start = task.get_start()
+	}
+	if s.n_registered == 0 || task.get_finish() > s.latest_finish {
+		s.latest_finish = task.get_finish()
+	}
+	if s.n_registered == 0 || task.get_launched() < s.earliest_launch {
+		s.earliest_launch = task.get_launched()
+	}
+	if s.n_registered == 0 || task.get_launched() > s.latest_launch {
+		s.latest_launch = task.get_launched()
+	}
+	if s.n_registered == 0 || task.get_duration() < s.min_duration {
+		s.min_duration = task.get_duration()
+	}
+	if s.n_registered == 0 || task.get_duration() > s.max_duration {
+		s.max_duration = task.get_duration()
+	}
+
+	s.n_registered++
+	s.n_by_status[task.get_status()]++
+	s.sum_duration += task.get_duration()
+	s.nominal_cycles += task.get_n_cycles()
+	s.completed_runs += task.get_n_runs()
+
+	if task.get_status() == TS_Done {
+		s.completed_cycles += task.get_n_cycles()
+		s.done_runs += task.get_n_runs()
+	}
+
+	if task.is_cpu_timed() {
+		s.n_cpu_timed++
+		s.cpu_timed_wall += task.get_duration()
+		s.sum_user_time += task.get_user_time()
+		s.sum_system_time += task.get_system_time()
+	}
+
+	if task.is_measured() {
+		s.n_measured++
+		s.sum_measured += task.get_duration()
+		delta := to_ms(task.get_duration()) - s.mean
+		s.mean += delta / float64(s.n_measured)
+		s.m2 += delta * (to_ms(task.get_duration()) - s.mean)
+	}
+}
+
+func (s *TaskStats) clone() *TaskStats {
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	copied := *s
+	copied.lock = &sync.Mutex{}
+	copied.n_by_status = map[TaskStatus]int{}
+
+	for status, count := range s.n_by_status {
+		copied.n_by_status[status] = count
+	}
+
+	return &copied
+}
+
+func (s TaskStats) count_tasks_with_status(status TaskStatus) int {
+	if status == TS_Pending {
+		return s.n_tasks - s.n_registered
+	} else {
+		return s.n_by_status[status]
+	}
+}
+
+func (s TaskStats) get_variance() float64 {
+	if s.n_measured > 1 {
+		return s.m2 / float64(s.n_measured-1)
+	} else {
+		return 0
+	}
+}
+
+func (s *TaskStats) recalc_relative(initial_moment time.Duration) {
+	s.earliest_start -= initial_moment
+	s.latest_finish -= initial_moment
+	s.earliest_launch -= initial_moment
+	s.latest_launch -= initial_moment
+}
+
+func create_task_stats(n_tasks int) *TaskStats {
+	return &TaskStats{&sync.Mutex{}, n_tasks, 0, map[TaskStatus]int{}, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+}
+
+type Observation struct {
+	workload_name      string
+	executor_name      string
+	tasks              []Task
+	concurrency_cost   float64
+	concurrency_profit float64
+	speedup            float64
+	first_failure      error
+	rep_idx            int
+	thread_locked      bool
+	ramp_workers       int
+	ramp_period        time.Duration
+	bound              time.Duration
+	throughput_speedup float64
+	series_size        int
+	n_noise            int
+	graph_layers       int
+	critical_path      time.Duration
+	offered_rate       float64
+	outlier_rule       OutlierRule
+	max_cv             float64
+	histogram          *Histogram
+	energy_uj          int64
+	ci_method          ConfidenceMethod
+	stats              *TaskStats
+	n_gc               int
+	gc_pause           time.Duration
+	heap_growth        int64
+	alloc_bytes        int64
+	n_mallocs          int64
+	perf_counts        PerfCounts
+	voluntary_cs       int64
+	involuntary_cs     int64
+	goroutine_samples  []GoroutineSample
+	threads_before     int
+	threads_after      int
+	threads_created    int
+	n_cycles           int
+	experiment_idx     int
+	gomaxprocs         int
+	// The baseline of the experiment the metrics were last calculated with
+	baseline_ms_per_cycle float64
+}
+
+// Cycles of a task as set up, before the sizing distribution applies
+func (o Observation) get_n_cycles() int {
+	return o.n_cycles
+}
+
+// Experiments of a batch count from 1, a run without a batch has 0
+func (o Observation) get_experiment_idx() int {
+	return o.experiment_idx
+}
+
+func (o *Observation) set_experiment_idx(experiment_idx int) {
+	o.experiment_idx = experiment_idx
+}
+
+func (o *Observation) set_n_cycles(n_cycles int) {
+	o.n_cycles = n_cycles
+}
+
+// A streamed observation keeps statistics instead of its tasks,
+// so tables of single tasks are empty for it
+func (o Observation) is_streamed() bool {
+	return o.stats != nil
+}
+
+func (o Observation) get_ci_method() ConfidenceMethod {
+	return o.ci_method
+}
+
+func (o *Observation) set_ci_method(ci_method ConfidenceMethod) {
+	o.ci_method = ci_method
+}
+
+// Energy is measured where RAPL counters are readable
+func (o Observation) is_energy_measured() bool {
+	return o.energy_uj >= 0
+}
+
+func (o *Observation) set_energy_uj(energy_uj int64) {
+	o.energy_uj = energy_uj
+}
+
+func (o Observation) get_energy_joules() float64 {
+	return float64(o.energy_uj) / 1e6
+}
+
+func (o Observation) get_joules_per_task() float64 {
+	return o.get_energy_joules() / float64(o.count_tasks())
+}
+
+// Garbage collections that ran during the observation
+// and their total stop-the-world pause
+func (o Observation) count_gc() int {
+	return o.n_gc
+}
+
+func (o Observation) get_gc_pause() time.Duration {
+	return o.gc_pause
+}
+
+func (o *Observation) set_gc(n_gc int, gc_pause time.Duration) {
+	o.n_gc = n_gc
+	o.gc_pause = gc_pause
+}
+
+// The share of the total duration the world was stopped for collections
+func (o Observation) get_gc_pause_share() float64 {
+	if o.get_total_duration() > 0 {
+		return float64(o.get_gc_pause()) / float64(o.get_total_duration())
+	} else {
+		return 0
+	}
+}
+
+// Growth of the heap in use, negative where collections freed more
+// than the tasks allocated, and the bytes and objects the tasks allocated
+func (o *Observation) set_memory(heap_growth, alloc_bytes, n_mallocs int64) {
+	o.heap_growth = heap_growth
+	o.alloc_bytes = alloc_bytes
+	o.n_mallocs = n_mallocs
+}
+
+func (o Observation) get_heap_growth() int64 {
+	return o.heap_growth
+}
+
+func (o Observation) get_alloc_bytes() int64 {
+	return o.alloc_bytes
+}
+
+func (o Observation) count_mallocs() int64 {
+	return o.n_mallocs
+}
+
+func (o Observation) get_alloc_bytes_per_task() float64 {
+	return float64(o.alloc_bytes) / float64(o.count_tasks())
+}
+
+// Context switches of the process during the observation: voluntary ones
+// where threads blocked, involuntary ones where the kernel preempted them;
+// -1 where they could not be read
+func (o Observation) count_voluntary_cs() int64 {
+	return o.voluntary_cs
+}
+
+func (o Observation) count_involuntary_cs() int64 {
+	return o.involuntary_cs
+}
+
+func (o *Observation) set_context_switches(voluntary_cs, involuntary_cs int64) {
+	o.voluntary_cs = voluntary_cs
+	o.involuntary_cs = involuntary_cs
+}
+
+func (o Observation) are_context_switches_read() bool {
+	return o.voluntary_cs >= 0
+}
+
+func (o Observation) get_context_switches_per_task() float64 {
+	return float64(o.voluntary_cs+o.involuntary_cs) / float64(o.count_tasks())
+}
+
+// OS threads of the process before and after the observation, -1 where
+// they could not be counted, and the threads the runtime created meanwhile;
+// blocking system calls and cgo calls make the runtime add threads
+func (o *Observation) set_threads(threads_before, threads_after, threads_created int) {
+	o.threads_before = threads_before
+	o.threads_after = threads_after
+	o.threads_created = threads_created
+}
+
+func (o Observation) count_threads_before() int {
+	return o.threads_before
+}
+
+func (o Observation) count_threads_after() int {
+	return o.threads_after
+}
+
+func (o Observation) count_threads_created() int {
+	return o.threads_created
+}
+
+func (o Observation) get_goroutine_samples() []GoroutineSample {
+	return o.goroutine_samples
+}
+
+func (o *Observation) set_goroutine_samples(samples []GoroutineSample) {
+	o.goroutine_samples = samples
+}
+
+func (o Observation) get_max_sampled_goroutines() int {
+
+	max_goroutines := 0
+
+	for _, sample := range o.goroutine_samples {
+		max_goroutines = max(max_goroutines, sample.n_goroutines)
+	}
+
+	return max_goroutines
+}
+
+func (o Observation) get_mean_sampled_goroutines() float64 {
+
+	if len(o.goroutine_samples) == 0 {
+		return 0
+	}
+
+	sum := 0
+
+	for _, sample := range o.goroutine_samples {
+		sum += sample.n_goroutines
+	}
+
+	return float64(sum) / float64(len(o.goroutine_samples))
+}
+
+// Counts of hardware events, counted with --perf where Linux allows
+func (o Observation) get_perf_counts() PerfCounts {
+	return o.perf_counts
+}
+
+func (o *Observation) set_perf_counts(perf_counts PerfCounts) {
+	o.perf_counts = perf_counts
+}
+
+func (o Observation) is_perf_counted() bool {
+	return o.perf_counts != create_perf_counts()
+}
+
+func (o Observation) has_histogram() bool {
+	return o.histogram != nil
+}
+
+func (o Observation) get_histogram() *Histogram {
+	return o.histogram
+}
+
+func (o *Observation) set_histogram(histogram *Histogram) {
+	o.histogram = histogram
+}
+
+func (o Observation) get_offered_rate() float64 {
+	return o.offered_rate
+}
+
+func (o *Observation) set_offered_rate(offered_rate float64) {
+	o.offered_rate = offered_rate
+}
+
+// Launches per second between the first and the last launch, 0 for a single launch
+func (o Observation) get_achieved_rate() float64 {
+
+	n_launched := o.count_tasks() - o.count_tasks_with_status(TS_Pending)
+	span := o.get_latest_launch() - o.get_earliest_launch()
+
+	if n_launched > 1 && span > 0 {
+		return float64(n_launched-1) / span.Seconds()
+	} else {
+		return 0
+	}
+}
+
+// Comparing CPU time with wall time
+
+// Tasks with their CPU time measured, their wall time,
+// and their CPU time in user and system mode
+func (o Observation) sum_cpu_times() (int, time.Duration, time.Duration, time.Duration) {
+
+	if o.is_streamed() {
+		return o.stats.n_cpu_timed, o.stats.cpu_timed_wall, o.stats.sum_user_time, o.stats.sum_system_time
+	}
+
+	n_timed := 0
+	var wall, user, system time.Duration = 0, 0, 0
+
+	for _, task := range o.tasks {
+		if task.is_cpu_timed() {
+			n_timed++
+			wall += task.get_duration()
+			user += task.get_user_time()
+			system += task.get_system_time()
+		}
+	}
+
+	return n_timed, wall, user, system
+}
+
+func (o Observation) is_cpu_timed() bool {
+	n_timed, _, _, _ := o.sum_cpu_times()
+	return n_timed > 0
+}
+
+func (o Observation) get_mean_user_time() time.Duration {
+	if n_timed, _, user, _ := o.sum_cpu_times(); n_timed > 0 {
+		return user / time.Duration(n_timed)
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) get_mean_system_time() time.Duration {
+	if n_timed, _, _, system := o.sum_cpu_times(); n_timed > 0 {
+		return system / time.Duration(n_timed)
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) get_mean_cpu_time() time.Duration {
+	return o.get_mean_user_time() + o.get_mean_system_time()
+}
+
+// The share of wall time the tasks spent on a CPU; for the rest
+// they waited for a CPU, a lock, or I/O
+func (o Observation) get_cpu_share() float64 {
+	if _, wall, user, system := o.sum_cpu_times(); wall > 0 {
+		return float64(user+system) / float64(wall)
+	} else {
+		return 0
+	}
+}
+
+// Checking Little's law, L = λW, on rate-driven observations
+
+func (o Observation) is_rate_driven() bool {
+	return o.offered_rate > 0
+}
+
+func (o Observation) sum_latency() time.Duration {
+
+	var sum time.Duration = 0
+
+	for _, task := range o.tasks {
+		if !task.is_pending() {
+			sum += task.get_latency()
+		}
+	}
+
+	return sum
+}
+
+// Time-average number of tasks launched but not finished, L, over
+// the window from the earliest launch to the latest finish
+func (o Observation) get_mean_in_system() float64 {
+
+	window := o.get_latest_finish() - o.get_earliest_launch()
+
+	if window > 0 {
+		return float64(o.sum_latency()) / float64(window)
+	} else {
+		return 0
+	}
+}
+
+// Arrival rate times mean latency, λW, with the rate per millisecond
+func (o Observation) get_rate_latency_product() float64 {
+
+	n_launched := o.count_tasks() - o.count_tasks_with_status(TS_Pending)
+
+	if n_launched > 0 {
+		return o.get_achieved_rate() * o.sum_latency().Seconds() / float64(n_launched)
+	} else {
+		return 0
+	}
+}
+
+// How far λW is from L, in percent of L; a queue still draining after
+// the last arrival makes it large
+func (o Observation) get_littles_law_deviation() float64 {
+	if o.get_mean_in_system() > 0 && o.get_achieved_rate() > 0 {
+		return (o.get_rate_latency_product() - o.get_mean_in_system()) * 100 / o.get_mean_in_system()
+	} else {
+		return 0
+	}
+}
+
+// Arrival rate times mean service time per slot, ρ; from 1 on
+// tasks arrive faster than the slots serve them
+func (o Observation) get_offered_load() float64 {
+	return o.get_achieved_rate() * o.get_mean_task_duration().Seconds() / float64(o.count_slots())
+}
+
+func (o Observation) is_saturated() bool {
+	return o.get_offered_load() >= 1
+}
+
+func (o Observation) is_graph() bool {
+	return o.graph_layers > 0
+}
+
+func (o *Observation) set_graph(graph_layers int, critical_path time.Duration) {
+	o.graph_layers = graph_layers
+	o.critical_path = critical_path
+}
+
+func (o Observation) count_graph_layers() int {
+	return o.graph_layers
+}
+
+func (o Observation) get_critical_path() time.Duration {
+	return o.critical_path
+}
+
+func (o Observation) get_total_work() time.Duration {
+
+	var work time.Duration = 0
+
+	for _, task := range o.tasks {
+		if task.get_status() == TS_Done {
+			work += task.get_duration()
+		}
+	}
+
+	return work
+}
+
+// The speedup that dependencies allow at most, whatever the number of CPUs
+func (o Observation) get_parallelism() float64 {
+	if o.get_critical_path() > 0 {
+		return float64(o.get_total_work()) / float64(o.get_critical_path())
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) get_n_noise() int {
+	return o.n_noise
+}
+
+func (o *Observation) set_n_noise(n_noise int) {
+	o.n_noise = n_noise
+}
+
+func (o Observation) get_series_size() int {
+	return o.series_size
+}
+
+func (o *Observation) set_series_size(series_size int) {
+	o.series_size = series_size
+}
+
+// Marks each task with the series it was launched in, the same way
+// execute_batches splits the launch order
+func (o *Observation) assign_series(launcher Launcher, series_size int) {
+
+	if o.is_streamed() {
+		return
+	}
+
+	for position := 0; position < launcher.count_tasks(); position++ {
+		o.tasks[launcher.get_task_idx(position)].set_series_idx(position / series_size)
+	}
+}
+
+func (o Observation) count_series() int {
+
+	n_series := 0
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && task.get_series_idx() >= n_series {
+			n_series = task.get_series_idx() + 1
+		}
+	}
+
+	return n_series
+}
+
+func (o Observation) has_series() bool {
+	return o.count_series() > 0
+}
+
+func (o Observation) get_series_window(series_idx int) (time.Duration, time.Duration) {
+
+	var series_start, series_finish time.Duration = 0, 0
+	found := false
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && task.get_series_idx() == series_idx {
+			if !found || task.get_start() < series_start {
+				series_start = task.get_start()
+			}
+			if !found || task.get_finish() > series_finish {
+				series_finish = task.get_finish()
+			}
+			found = true
+		}
+	}
+
+	return series_start, series_finish
+}
+
+func (o Observation) get_series_duration(series_idx int) time.Duration {
+	series_start, series_finish := o.get_series_window(series_idx)
+	return series_finish - series_start
+}
+
+// Slot milliseconds spent by finished tasks of the series waiting
+// for its slowest task before the next series may start
+func (o Observation) get_series_idle_tail(series_idx int) time.Duration {
+
+	_, series_finish := o.get_series_window(series_idx)
+	var idle_tail time.Duration = 0
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && task.get_series_idx() == series_idx {
+			idle_tail += series_finish - task.get_finish()
+		}
+	}
+
+	return idle_tail
+}
+
+func (o Observation) get_mean_series_duration() time.Duration {
+
+	n_series := o.count_series()
+	var sum time.Duration = 0
+
+	for series_idx := 0; series_idx < n_series; series_idx++ {
+		sum += o.get_series_duration(series_idx)
+	}
+
+	if n_series > 0 {
+		return sum / time.Duration(n_series)
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) sum_series_idle_tails() time.Duration {
+
+	var sum time.Duration = 0
+
+	for series_idx := 0; series_idx < o.count_series(); series_idx++ {
+		sum += o.get_series_idle_tail(series_idx)
+	}
+
+	return sum
+}
+
+// Share of the slot time within series that is lost at the tails, in percent
+func (o Observation) get_idle_tail_share() float64 {
+
+	var slot_time time.Duration = 0
+
+	for series_idx := 0; series_idx < o.count_series(); series_idx++ {
+		slot_time += time.Duration(o.series_size) * o.get_series_duration(series_idx)
+	}
+
+	if slot_time > 0 {
+		return float64(o.sum_series_idle_tails()) * 100 / float64(slot_time)
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) is_thread_locked() bool {
+	return o.thread_locked
+}
+
+func (o *Observation) set_thread_locked(thread_locked bool) {
+	o.thread_locked = thread_locked
+}
+
+func (o Observation) is_bounded() bool {
+	return o.bound > 0
+}
+
+func (o *Observation) set_bound(bound time.Duration) {
+	o.bound = bound
+}
+
+func (o Observation) count_completed_runs() int {
+
+	if o.is_streamed() {
+		return o.stats.completed_runs
+	}
+
+	n_runs := 0
+
+	for _, task := range o.tasks {
+		n_runs += task.get_n_runs()
+	}
+
+	return n_runs
+}
+
+func (o Observation) count_completed_cycles() int {
+
+	if o.is_streamed() {
+		return o.stats.completed_cycles
+	}
+
+	n_cycles := 0
+
+	for _, task := range o.tasks {
+		if task.get_status() == TS_Done {
+			n_cycles += task.get_n_cycles()
+		}
+	}
+
+	return n_cycles
+}
+
+func (o Observation) get_cycles_per_sec() float64 {
+	if o.get_total_duration() > 0 {
+		return float64(o.count_completed_cycles()) / o.get_total_duration().Seconds()
+	} else {
+		return 0
+	}
+}
+
+// Runs of tasks that completed all of their runs
+func (o Observation) count_done_runs() int {
+
+	if o.is_streamed() {
+		return o.stats.done_runs
+	}
+
+	n_runs := 0
+
+	for _, task := range o.tasks {
+		if task.get_status() == TS_Done {
+			n_runs += task.get_n_runs()
+		}
+	}
+
+	return n_runs
+}
+
+// Completed runs of tasks per second of wall time
+func (o Observation) get_tasks_per_sec() float64 {
+	if o.get_total_duration() > 0 {
+		return float64(o.count_done_runs()) / o.get_total_duration().Seconds()
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) get_throughput_speedup() float64 {
+	return o.throughput_speedup
+}
+
+func (o *Observation) calc_throughput_speedup(baseline_cycles_per_sec float64) float64 {
+
+	if baseline_cycles_per_sec > 0 {
+		o.throughput_speedup = o.get_cycles_per_sec() / baseline_cycles_per_sec
+	} else {
+		o.throughput_speedup = 0
+	}
+
+	return o.throughput_speedup
+}
+
+func (o Observation) is_ramped() bool {
+	return o.ramp_workers > 0
+}
+
+func (o *Observation) set_ramp(ramp_workers int, ramp_period time.Duration) {
+	o.ramp_workers = ramp_workers
+	o.ramp_period = ramp_period
+}
+
+// The time span, end exclusive, while the given number of ramp workers was active
+func (o Observation) get_ramp_window(n_workers int) (time.Duration, time.Duration) {
+
+	window_start := time.Duration(n_workers-1) * o.ramp_period
+
+	if n_workers < o.ramp_workers {
+		return window_start, window_start + o.ramp_period
+	} else {
+		return window_start, max(window_start, o.get_latest_finish()) + 1
+	}
+}
+
+func (o Observation) count_tasks_finished_within(window_start, window_finish time.Duration) int {
+
+	count := 0
+
+	for _, task := range o.tasks {
+		if task.get_status() == TS_Done &&
+			task.get_finish() >= window_start &&
+			task.get_finish() < window_finish {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (o Observation) get_ramp_throughput(n_workers int) float64 {
+
+	window_start, window_finish := o.get_ramp_window(n_workers)
+
+	if window_finish <= window_start {
+		return 0
+	} else {
+		n_finished := o.count_tasks_finished_within(window_start, window_finish)
+		return float64(n_finished) / (window_finish - window_start).Seconds()
+	}
+}
+
+func (o Observation) get_rep_idx() int {
+	return o.rep_idx
+}
+
+func (o *Observation) set_rep_idx(rep_idx int) {
+	o.rep_idx = rep_idx
+}
+
+func (o Observation) get_first_failure() error {
+	return o.first_failure
+}
+
+func (o *Observation) set_first_failure(err error) {
+	o.first_failure = err
+}
+
+func (o Observation) get_workload_name() string {
+	return o.workload_name
+}
+
+func (o Observation) get_executor_name() string {
+	return o.executor_name
+}
+
+// Swept series sizes and cycles make experiments of their own
+func (o Observation) is_same_experiment(other *Observation) bool {
+	return o.get_workload_name() == other.get_workload_name() &&
+		o.get_executor_name() == other.get_executor_name() &&
+		o.is_thread_locked() == other.is_thread_locked() &&
+		o.get_series_size() == other.get_series_size() &&
+		o.get_n_cycles() == other.get_n_cycles() &&
+		o.get_experiment_idx() == other.get_experiment_idx()
+}
+
+// Safe to call from concurrent tasks: a task writes only the element of
+// its own index, which no other task touches, and the wait for the task
+// group orders the writes before anything reads the tasks
+func (o *Observation) register_task(task Task) {
+	if o.is_streamed() {
+		o.stats.add(task)
+	} else {
+		o.tasks[task.get_idx()] = task
+	}
+}
+
+// A copy sharing nothing the copy or the original could change, for
+// handing observations out of the package
+func (o Observation) clone() Observation {
+
+	o.tasks = append([]Task(nil), o.tasks...)
+	o.goroutine_samples = append([]GoroutineSample(nil), o.goroutine_samples...)
+
+	if o.histogram != nil {
+		o.histogram = o.histogram.clone()
+	}
+
+	if o.stats != nil {
+		o.stats = o.stats.clone()
+	}
+
+	return o
+}
+
+func (o Observation) count_tasks() int {
+	if o.is_streamed() {
+		return o.stats.n_tasks
+	} else {
+		return len(o.tasks)
+	}
+}
+
+func (o Observation) get_earliest_start() time.Duration {
+
+	if o.is_streamed() {
+		return o.stats.earliest_start
+	}
+
+	earliest_start := o.tasks[0].get_start()
+	found := false
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && (!found || earliest_start > task.get_start()) {
+			earliest_start = task.get_start()
+			found = true
+		}
+	}
+
+	return earliest_start
+}
+
+func (o Observation) get_latest_finish() time.Duration {
+
+	if o.is_streamed() {
+		return o.stats.latest_finish
+	}
+
+	latest_finish := o.tasks[0].get_finish()
+	found := false
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && (!found || latest_finish < task.get_finish()) {
+			latest_finish = task.get_finish()
+			found = true
+		}
+	}
+
+	return latest_finish
+}
+
+func (o Observation) get_earliest_launch() time.Duration {
+
+	if o.is_streamed() {
+		return o.stats.earliest_launch
+	}
+
+	earliest_launch := o.get_earliest_start()
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && earliest_launch > task.get_launched() {
+			earliest_launch = task.get_launched()
+		}
+	}
+
+	return earliest_launch
+}
+
+func (o Observation) get_latest_launch() time.Duration {
+
+	if o.is_streamed() {
+		return o.stats.latest_launch
+	}
+
+	latest_launch := o.get_earliest_launch()
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && latest_launch < task.get_launched() {
+			latest_launch = task.get_launched()
+		}
+	}
+
+	return latest_launch
+}
+
+func (o Observation) recalc_tasks_relative_earliest_start() {
+
+	earliest_launch := o.get_earliest_launch()
+
+	if o.is_streamed() {
+		o.stats.recalc_relative(earliest_launch)
+	}
+
+	for task_idx := range o.tasks {
+		if !o.tasks[task_idx].is_pending() {
+			o.tasks[task_idx].recalc_start_relative(earliest_launch)
+		}
+	}
+
+	for sample_idx := range o.goroutine_samples {
+		o.goroutine_samples[sample_idx].moment -= earliest_launch
+	}
+}
+
+func (o Observation) get_total_duration() time.Duration {
+	return o.get_latest_finish() - o.get_earliest_start()
+}
+
+// A stretch of time with the same number of tasks running
+type ConcurrencyLevel struct {
+	from     time.Duration
+	to       time.Duration
+	n_active int
+}
+
+// Stretches of equal concurrency from the earliest start to the latest finish,
+// bounded by the moments tasks start and finish
+func (o Observation) collect_concurrency_levels() []ConcurrencyLevel {
+
+	changes := map[time.Duration]int{}
+
+	for _, task := range o.tasks {
+		if !task.is_pending() {
+			changes[task.get_start()]++
+			changes[task.get_finish()]--
+		}
+	}
+
+	moments := make([]time.Duration, 0, len(changes))
+	for moment := range changes {
+		moments = append(moments, moment)
+	}
+	sort.Slice(moments, func(i, j int) bool {
+		return moments[i] < moments[j]
+	})
+
+	levels := []ConcurrencyLevel{}
+	n_active := 0
+
+	for idx := 0; idx+1 < len(moments); idx++ {
+		n_active += changes[moments[idx]]
+		if n_levels := len(levels); n_levels > 0 && levels[n_levels-1].n_active == n_active {
+			levels[n_levels-1].to = moments[idx+1]
+		} else {
+			levels = append(levels, ConcurrencyLevel{moments[idx], moments[idx+1], n_active})
+		}
+	}
+
+	return levels
+}
+
+func (o Observation) get_latest_start() time.Duration {
+
+	latest_start := o.get_earliest_start()
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && latest_start < task.get_start() {
+			latest_start = task.get_start()
+		}
+	}
+
+	return latest_start
+}
+
+// Tasks the executor may run at once
+func (o Observation) count_slots() int {
+	if o.series_size > 0 && o.series_size < o.count_tasks() {
+		return o.series_size
+	} else {
+		return o.count_tasks()
+	}
+}
+
+// Splits the slot time, slots times the total duration, into slot time
+// that is busy, idle before the last task starts, and idle after it while
+// waiting for stragglers; more tasks than slots count as busy slots
+func (o Observation) break_down_makespan() (time.Duration, time.Duration, time.Duration) {
+
+	slots := o.count_slots()
+	latest_start := o.get_latest_start()
+	var work, gaps, tail time.Duration = 0, 0, 0
+
+	for _, level := range o.collect_concurrency_levels() {
+		busy := min(level.n_active, slots)
+		span := level.to - level.from
+		work += time.Duration(busy) * span
+		if level.from < latest_start {
+			gaps += time.Duration(slots-busy) * span
+		} else {
+			tail += time.Duration(slots-busy) * span
+		}
+	}
+
+	return work, gaps, tail
+}
+
+// Shares of the slot time busy, lost in gaps, and lost at the tail, in percent
+func (o Observation) get_makespan_shares() (float64, float64, float64) {
+
+	if o.is_streamed() {
+		return 0, 0, 0
+	}
+
+	work, gaps, tail := o.break_down_makespan()
+	slot_time := float64(work + gaps + tail)
+
+	if slot_time > 0 {
+		return float64(work) * 100 / slot_time, float64(gaps) * 100 / slot_time, float64(tail) * 100 / slot_time
+	} else {
+		return 0, 0, 0
+	}
+}
+
+// Task statistics count the tasks done and leave out those cut short
+func (o Observation) count_measured_tasks() int {
+	return o.count_tasks_with_status(TS_Done)
+}
+
+func (o Observation) sum_duration() time.Duration {
+
+	if o.is_streamed() {
+		return o.stats.sum_duration
+	}
+
+	var sum time.Duration = 0
+
+	for _, task := range o.tasks {
+		sum += task.get_duration()
+	}
+
+	return sum
+}
+
+func (o Observation) sum_measured_duration() time.Duration {
+
+	if o.is_streamed() {
+		return o.stats.sum_measured
+	}
+
+	var sum time.Duration = 0
+
+	for _, task := range o.tasks {
+		if task.is_measured() {
+			sum += task.get_duration()
+		}
+	}
+
+	return sum
+}
+
+func (o Observation) get_mean_queue_wait() time.Duration {
+
+	var sum time.Duration = 0
+
+	for _, task := range o.tasks {
+		sum += task.get_queue_wait()
+	}
+
+	return sum / time.Duration(o.count_tasks())
+}
+
+func (o Observation) get_max_queue_wait() time.Duration {
+
+	var max_wait time.Duration = 0
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && task.get_queue_wait() > max_wait {
+			max_wait = task.get_queue_wait()
+		}
+	}
+
+	return max_wait
+}
+
+// The share of the latency from submitting tasks to their finish
+// that they waited for a worker or a slot rather than ran
+func (o Observation) get_wait_share() float64 {
+
+	var wait, latency time.Duration = 0, 0
+
+	for _, task := range o.tasks {
+		if !task.is_pending() {
+			wait += task.get_queue_wait()
+			latency += task.get_latency()
+		}
+	}
+
+	if latency > 0 {
+		return float64(wait) / float64(latency)
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) get_mean_latency() time.Duration {
+
+	var sum time.Duration = 0
+
+	for _, task := range o.tasks {
+		sum += task.get_latency()
+	}
+
+	return sum / time.Duration(o.count_tasks())
+}
+
+func (o Observation) get_max_latency() time.Duration {
+
+	var max_latency time.Duration = 0
+
+	for _, task := range o.tasks {
+		if task.get_latency() > max_latency {
+			max_latency = task.get_latency()
+		}
+	}
+
+	return max_latency
+}
+
+func (o Observation) get_mean_sched_latency() time.Duration {
+
+	var sum time.Duration = 0
+
+	for _, task := range o.tasks {
+		sum += task.get_sched_latency()
+	}
+
+	return sum / time.Duration(o.count_tasks())
+}
+
+func (o Observation) get_max_sched_latency() time.Duration {
+
+	var max_latency time.Duration = 0
+
+	for _, task := range o.tasks {
+		if task.get_sched_latency() > max_latency {
+			max_latency = task.get_sched_latency()
+		}
+	}
+
+	return max_latency
+}
+
+func (o Observation) get_mean_task_duration() time.Duration {
+	if o.count_measured_tasks() > 0 {
+		return o.sum_measured_duration() / time.Duration(o.count_measured_tasks())
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) collect_measured_durations() []float64 {
+
+	durations := []float64{}
+
+	for _, task := range o.tasks {
+		if task.is_measured() {
+			durations = append(durations, to_ms(task.get_duration()))
+		}
+	}
+
+	return durations
+}
+
+func (o Observation) get_outlier_rule() OutlierRule {
+	return o.outlier_rule
+}
+
+func (o *Observation) set_outlier_rule(outlier_rule OutlierRule) {
+	o.outlier_rule = outlier_rule
+}
+
+// Sorting the durations takes a while, so callers going over the tasks
+// find the bounds once for all of them
+func (o Observation) get_outlier_bounds() (float64, float64) {
+	if o.get_outlier_rule() == OR_None {
+		return math.Inf(-1), math.Inf(1)
+	} else {
+		return find_outlier_bounds(o.collect_measured_durations(), o.get_outlier_rule())
+	}
+}
+
+func (o Observation) is_outlier(task *Task, low, high float64) bool {
+
+	if !task.is_measured() {
+		return false
+	}
+
+	duration := to_ms(task.get_duration())
+
+	return duration < low || duration > high
+}
+
+func (o Observation) count_outliers() int {
+
+	low, high := o.get_outlier_bounds()
+	n_outliers := 0
+
+	for idx := range o.tasks {
+		if o.is_outlier(&o.tasks[idx], low, high) {
+			n_outliers++
+		}
+	}
+
+	return n_outliers
+}
+
+func (o Observation) collect_trimmed_durations() []float64 {
+
+	low, high := o.get_outlier_bounds()
+	durations := []float64{}
+
+	for _, duration := range o.collect_measured_durations() {
+		if duration >= low && duration <= high {
+			durations = append(durations, duration)
+		}
+	}
+
+	return durations
+}
+
+func (o Observation) get_trimmed_mean() float64 {
+
+	durations := o.collect_trimmed_durations()
+
+	if len(durations) > 0 {
+		return mean_of(durations)
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) get_trimmed_standard_deviation() float64 {
+	return standard_deviation_of(o.collect_trimmed_durations())
+}
+
+// The index of the slowest finished or interrupted task, -1 if all are pending
+func (o Observation) find_slowest_task() int {
+
+	slowest_idx := -1
+
+	for idx, task := range o.tasks {
+		if !task.is_pending() && (slowest_idx < 0 || task.get_duration() > o.tasks[slowest_idx].get_duration()) {
+			slowest_idx = idx
+		}
+	}
+
+	return slowest_idx
+}
+
+func (o Observation) get_max_task_duration() time.Duration {
+	if o.is_streamed() {
+		return o.stats.max_duration
+	} else if slowest_idx := o.find_slowest_task(); slowest_idx >= 0 {
+		return o.tasks[slowest_idx].get_duration()
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) get_min_task_duration() time.Duration {
+
+	if o.is_streamed() {
+		return o.stats.min_duration
+	}
+
+	var min_duration time.Duration = 0
+	found := false
+
+	for _, task := range o.tasks {
+		if !task.is_pending() && (!found || task.get_duration() < min_duration) {
+			min_duration = task.get_duration()
+			found = true
+		}
+	}
+
+	return min_duration
+}
+
+func (o Observation) get_duration_percentile(percentile float64) float64 {
+	return percentile_of(o.collect_measured_durations(), percentile)
+}
+
+// Sample variance of task durations
+func (o Observation) get_variance() float64 {
+
+	if o.is_streamed() {
+		return o.stats.get_variance()
+	}
+
+	n_measured := o.count_measured_tasks()
+
+	if n_measured > 1 {
+
+		mean_task_duration := to_ms(o.sum_measured_duration()) / float64(n_measured)
+		dispersion := 0.0
+
+		for _, task := range o.tasks {
+			if task.is_measured() {
+				deviation := to_ms(task.get_duration()) - mean_task_duration
+				dispersion += deviation * deviation
+			}
+		}
+
+		return dispersion / float64(n_measured-1)
+	} else {
+		return 0
+	}
+}
+
+func (o Observation) get_standard_deviation() float64 {
+	return math.Sqrt(o.get_variance())
+}
+
+func (o Observation) get_skewness() float64 {
+	return skewness_of(o.collect_measured_durations())
+}
+
+func (o Observation) get_kurtosis() float64 {
+	return kurtosis_of(o.collect_measured_durations())
+}
+
+// Coefficient of variation of task durations, 0 if there is no mean
+func (o Observation) get_cv() float64 {
+
+	if o.sum_measured_duration() > 0 {
+		mean := to_ms(o.sum_measured_duration()) / float64(o.count_measured_tasks())
+		return o.get_standard_deviation() / mean
+	} else {
+		return 0
+	}
+}
+
+func (o *Observation) set_max_cv(max_cv float64) {
+	o.max_cv = max_cv
+}
+
+// Tasks that differ too much suggest the machine was busy with something else
+func (o Observation) is_unreliable() bool {
+	return o.max_cv > 0 && o.get_cv() > o.max_cv
+}
+
+func (o Observation) count_tasks_with_status(status TaskStatus) int {
+
+	if o.is_streamed() {
+		return o.stats.count_tasks_with_status(status)
+	}
+
+	count := 0
+
+	for _, task := range o.tasks {
+		if task.get_status() == status {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (o Observation) is_interrupted() bool {
+	return o.count_tasks_with_status(TS_Done) < o.count_tasks()
+}
+
+func (o Observation) count_stages() int {
+
+	n_stages := 0
+
+	for _, task := range o.tasks {
+		n_stages = max(n_stages, len(task.get_stage_times()))
+	}
+
+	return n_stages
+}
+
+func (o Observation) get_mean_stage_times() []time.Duration {
+
+	mean_stage_times := make([]time.Duration, o.count_stages())
+
+	for _, task := range o.tasks {
+		for stage_idx, stage_time := range task.get_stage_times() {
+			mean_stage_times[stage_idx] += stage_time
+		}
+	}
+
+	for stage_idx := range mean_stage_times {
+		mean_stage_times[stage_idx] /= time.Duration(o.count_tasks())
+	}
+
+	return mean_stage_times
+}
+
+// Cycles the tasks were sized with, which differ between tasks
+// unless the sizing is fixed
+func (o Observation) sum_nominal_cycles() int {
+
+	if o.is_streamed() {
+		return o.stats.nominal_cycles
+	}
+
+	n_cycles := 0
+
+	for _, task := range o.tasks {
+		if !task.is_pending() {
+			n_cycles += task.get_n_cycles()
+		}
+	}
+
+	return n_cycles
+}
+
+func (o Observation) get_ms_per_cycle() float64 {
+	if o.sum_nominal_cycles() > 0 {
+		return to_ms(o.get_total_duration()) / float64(o.sum_nominal_cycles())
+	} else {
+		return 0
+	}
+}
+
+// Quantities the metrics are computed from, each one with a symbol
+// that the formulas of the metrics refer to
+type Quantity = int
+
+const (
+	QT_TotalDuration Quantity = iota
+	QT_SumDuration
+	QT_Cycles
+	QT_BaselineMsPerCycle
+	QT_ParallelTasks
+)
+
+var QUANTITIES = []Quantity{QT_TotalDuration, QT_SumDuration, QT_Cycles, QT_BaselineMsPerCycle, QT_ParallelTasks}
+
+func get_quantity_symbol(quantity Quantity) string {
+	switch quantity {
+	case QT_TotalDuration:
+		return "T"
+	case QT_SumDuration:
+		return "S"
+	case QT_Cycles:
+		return "C"
+	case QT_BaselineMsPerCycle:
+		return "B"
+	default:
+		return "P"
+	}
+}
+
+func describe_quantity(quantity Quantity) string {
+	switch quantity {
+	case QT_TotalDuration:
+		return "Total duration ms from the earliest start to the latest finish"
+	case QT_SumDuration:
+		return "Sum of the task durations ms"
+	case QT_Cycles:
+		return "Sum of the cycles of the tasks as set up before the sizing distribution applies"
+	case QT_BaselineMsPerCycle:
+		return "Baseline ms per cycle: the total duration per cycle averaged over the repetitions of the first observation of the experiment"
+	default:
+		return "Tasks that could run in parallel: the least of the tasks and GOMAXPROCS"
+	}
+}
+
+func (o Observation) get_quantity(quantity Quantity) float64 {
+	switch quantity {
+	case QT_TotalDuration:
+		return to_ms(o.get_total_duration())
+	case QT_SumDuration:
+		return to_ms(o.sum_duration())
+	case QT_Cycles:
+		return float64(o.sum_nominal_cycles())
+	case QT_BaselineMsPerCycle:
+		return o.get_baseline_ms_per_cycle()
+	default:
+		return float64(o.count_parallel_tasks())
+	}
+}
+
+// A metric is computed by the formula it is explained with, B*C being the
+// ideal duration of the tasks run one after another in the baseline
+type Formula struct {
+	metric_name string
+	text        string
+	calc        func(q func(Quantity) float64) float64
+}
+
+var FORMULA_COST = Formula{"Concurrency cost", "1 - B*C/S",
+	func(q func(Quantity) float64) float64 {
+		return 1 - q(QT_BaselineMsPerCycle)*q(QT_Cycles)/q(QT_SumDuration)
+	}}
+
+var FORMULA_PROFIT = Formula{"Concurrency profit", "1 - T/(B*C)",
+	func(q func(Quantity) float64) float64 {
+		return 1 - q(QT_TotalDuration)/(q(QT_BaselineMsPerCycle)*q(QT_Cycles))
+	}}
+
+var FORMULA_SPEEDUP = Formula{"Speedup", "B*C/T",
+	func(q func(Quantity) float64) float64 {
+		if q(QT_TotalDuration) > 0 {
+			return q(QT_BaselineMsPerCycle) * q(QT_Cycles) / q(QT_TotalDuration)
+		} else {
+			return 0
+		}
+	}}
+
+var FORMULA_EFFICIENCY = Formula{"Efficiency", "B*C/(T*P)",
+	func(q func(Quantity) float64) float64 {
+		if q(QT_TotalDuration) > 0 {
+			return q(QT_BaselineMsPerCycle) * q(QT_Cycles) / (q(QT_TotalDuration) * q(QT_ParallelTasks))
+		} else {
+			return 0
+		}
+	}}
+
+var FORMULA_UTILIZATION = Formula{"Utilization", "S/(T*P)",
+	func(q func(Quantity) float64) float64 {
+		if q(QT_TotalDuration) > 0 {
+			return q(QT_SumDuration) / (q(QT_TotalDuration) * q(QT_ParallelTasks))
+		} else {
+			return 0
+		}
+	}}
+
+var FORMULAS = []Formula{FORMULA_COST, FORMULA_PROFIT, FORMULA_SPEEDUP, FORMULA_EFFICIENCY, FORMULA_UTILIZATION}
+
+func (f Formula) get_metric_name() string {
+	return f.metric_name
+}
+
+func (f Formula) get_text() string {
+	return f.text
+}
+
+func (f Formula) evaluate(obs *Observation) float64 {
+	return f.calc(obs.get_quantity)
+}
+
+// The formula with the quantities of the observation in place of the symbols
+func (f Formula) substitute(obs *Observation) string {
+
+	pairs := []string{}
+
+	for _, quantity := range QUANTITIES {
+		pairs = append(pairs, get_quantity_symbol(quantity), strconv.FormatFloat(obs.get_quantity(quantity), 'g', 6, 64))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(f.get_text())
+}
+
+func (o Observation) get_baseline_ms_per_cycle() float64 {
+	return o.baseline_ms_per_cycle
+}
+
+func (o *Observation) set_baseline_ms_per_cycle(ms_per_cycle float64) {
+	o.baseline_ms_per_cycle = ms_per_cycle
+}
+
+func (o Observation) get_speedup() float64 {
+	return o.speedup
+}
+
+func (o *Observation) calc_speedup() float64 {
+	o.speedup = FORMULA_SPEEDUP.evaluate(o)
+	return o.speedup
+}
+
+// GOMAXPROCS as it was when the observation was made, which a measurement
+// run by another program may change back afterwards
+func (o Observation) get_gomaxprocs() int {
+	return o.gomaxprocs
+}
+
+// Tasks that could run in parallel on the CPUs given to goroutines
+func (o Observation) count_parallel_tasks() int {
+	if o.count_tasks() < o.get_gomaxprocs() {
+		return o.count_tasks()
+	} else {
+		return o.get_gomaxprocs()
+	}
+}
+
+// Speedup per task that could run in parallel
+func (o Observation) get_efficiency() float64 {
+	return FORMULA_EFFICIENCY.evaluate(&o)
+}
+
+// The share of the CPU time available to the tasks that they were busy
+func (o Observation) get_utilization() float64 {
+	return FORMULA_UTILIZATION.evaluate(&o)
+}
+
+func (o Observation) get_concurrency_cost() float64 {
+	return o.concurrency_cost
+}
+
+func (o *Observation) calc_concurrency_cost() float64 {
+	o.concurrency_cost = FORMULA_COST.evaluate(o)
+	return o.concurrency_cost
+}
+
+func (o Observation) get_concurrency_profit() float64 {
+	return o.concurrency_profit
+}
+
+func (o *Observation) calc_concurrency_profit() float64 {
+	o.concurrency_profit = FORMULA_PROFIT.evaluate(o)
+	return o.concurrency_profit
+}
+
+func create_observation(workload_name, executor_name string, n_tasks int) Observation {
+
+	obs := Observation{workload_name, executor_name, []Task{}, 0.0, 0.0, 0.0, nil, 0, false, 0, 0, 0, 0.0, 0, 0, 0, 0, 0.0, OR_None, 0.0, nil, -1, CI_T, nil, 0, 0, 0, 0, 0, create_perf_counts(), -1, -1, nil, -1, -1, 0, 0, 0, runtime.GOMAXPROCS(0), 0}
+
+	for idx := 0; idx < n_tasks; idx++ {
+		task := create_task(idx, 0, 0, 0, nil)
+		task.set_status(TS_Pending)
+		obs.tasks = append(obs.tasks, task)
+	}
+
+	return obs
+}
+
+func create_streamed_observation(workload_name, executor_name string, n_tasks int) Observation {
+
+	obs := create_observation(workload_name, executor_name, 0)
+	obs.stats = create_task_stats(n_tasks)
+
+	return obs
+}
+
+type Report struct {
+	observations      []Observation
+	gomaxprocs        int
+	cold_starts       []ColdStart
+	cycles_per_sec    int
+	clock_cost        time.Duration
+	launch_cost       time.Duration
+	interruption      string
+	calibrated_cycles int
+	task_duration     time.Duration
+	round             int
+	started           time.Time
+	machine_id        string
+	machine           MachineProfile
+	seed              int64
+	labels            RunLabels
+}
+
+// The label and the tags given to the run
+func (r *Report) set_labels(labels RunLabels) {
+	r.labels = labels
+}
+
+func (r Report) get_labels() RunLabels {
+	return r.labels
+}
+
+// The seed of the random draws, which --seed repeats
+func (r *Report) set_seed(seed int64) {
+	r.seed = seed
+}
+
+func (r Report) get_seed() int64 {
+	return r.seed
+}
+
+// The system parameters checked before the measurement, with --sys
+func (r *Report) set_machine_profile(machine MachineProfile) {
+	r.machine = machine
+}
+
+func (r Report) get_machine_profile() MachineProfile {
+	return r.machine
+}
+
+func (r Report) is_machine_profiled() bool {
+	return r.machine.get_id() != ""
+}
+
+// The id of the machine profile the run refers to, empty for none
+func (r *Report) set_machine_id(machine_id string) {
+	r.machine_id = machine_id
+}
+
+func (r Report) get_machine_id() string {
+	return r.machine_id
+}
+
+// Rounds of a run repeated on an interval count from 1, a single run has 0
+func (r *Report) set_round(round int, started time.Time) {
+	r.round = round
+	r.started = started
+}
+
+func (r Report) get_round() int {
+	return r.round
+}
+
+func (r Report) get_started() time.Time {
+	return r.started
+}
+
+// A report of a run stopped early holds the observations completed
+// by then, and the reason it stopped
+func (r *Report) set_interruption(interruption string) {
+	r.interruption = interruption
+}
+
+func (r Report) get_interruption() string {
+	return r.interruption
+}
+
+func (r Report) is_partial() bool {
+	return r.interruption != ""
+}
+
+// What reading the clock once and launching a goroutine cost the harness
+func (r *Report) set_overheads(clock_cost, launch_cost time.Duration) {
+	r.clock_cost = clock_cost
+	r.launch_cost = launch_cost
+}
+
+func (r Report) get_clock_cost() time.Duration {
+	return r.clock_cost
+}
+
+func (r Report) get_launch_cost() time.Duration {
+	return r.launch_cost
+}
+
+// Time a task spends in the harness rather than in its workload
+func (r Report) get_harness_overhead() time.Duration {
+	return r.launch_cost + HARNESS_CLOCK_READINGS*r.clock_cost
+}
+
+// Observations from first_idx on whose mean task duration is so short
+// that the harness overhead takes a noticeable share of it
+func (r Report) count_overhead_dominated(first_idx int) int {
+
+	n_dominated := 0
+
+	for idx := first_idx; idx < r.count_observations(); idx++ {
+		obs := r.get_observation(idx)
+		if float64(r.get_harness_overhead()) > MAX_OVERHEAD_SHARE*float64(obs.get_mean_task_duration()) {
+			n_dominated++
+		}
+	}
+
+	return n_dominated
+}
+
+// 0 unless the machine speed was calibrated
+func (r Report) get_cycles_per_sec() int {
+	return r.cycles_per_sec
+}
+
+func (r *Report) set_cycles_per_sec(cycles_per_sec int) {
+	r.cycles_per_sec = cycles_per_sec
+}
+
+// Cycles chosen to make a task last the duration, 0 if given instead
+func (r *Report) set_calibration(calibrated_cycles int, task_duration time.Duration) {
+	r.calibrated_cycles = calibrated_cycles
+	r.task_duration = task_duration
+}
+
+func (r Report) get_calibrated_cycles() int {
+	return r.calibrated_cycles
+}
+
+func (r Report) get_task_duration() time.Duration {
+	return r.task_duration
+}
+
+func (r Report) is_calibrated() bool {
+	return r.calibrated_cycles > 0
+}
+
+func (r Report) is_normalized() bool {
+	return r.cycles_per_sec > 0
+}
+
+// Durations in cycles of the calibration loop take the speed of
+// the machine out, so that reports of different machines compare
+func (r Report) to_cycles(duration time.Duration) float64 {
+	return duration.Seconds() * float64(r.cycles_per_sec)
+}
+
+func (r Report) count_cold_starts() int {
+	return len(r.cold_starts)
+}
+
+func (r Report) get_cold_start(idx int) *ColdStart {
+	return &(r.cold_starts[idx])
+}
+
+func (r *Report) register_cold_start(cold_start ColdStart) {
+	r.cold_starts = append(r.cold_starts, cold_start)
+}
+
+func (r Report) get_gomaxprocs() int {
+	return r.gomaxprocs
+}
+
+func (r Report) count_observations() int {
+	return len(r.observations)
+}
+
+// Garbage collections during the observations from first_idx on
+func (r Report) count_gc(first_idx int) int {
+
+	n_gc := 0
+
+	for idx := first_idx; idx < r.count_observations(); idx++ {
+		n_gc += r.get_observation(idx).count_gc()
+	}
+
+	return n_gc
+}
+
+// OS threads created during the observations from first_idx on
+func (r Report) count_created_threads(first_idx int) int {
+
+	n_created := 0
+
+	for idx := first_idx; idx < r.count_observations(); idx++ {
+		n_created += r.get_observation(idx).count_threads_created()
+	}
+
+	return n_created
+}
+
+func (r Report) find_baseline(obs *Observation) *Observation {
+
+	for idx := range r.observations {
+		if r.observations[idx].is_same_experiment(obs) {
+			return &(r.observations[idx])
+		}
+	}
+
+	return nil
+}
+
+// Mean speedups over repetitions of observations registered since first_idx
+func (r Report) collect_scale_points(first_idx int) []ScalePoint {
+
+	points := []ScalePoint{}
+
+	for idx := first_idx; idx < r.count_observations(); idx++ {
+		if obs := r.get_observation(idx); obs.get_rep_idx() == 0 {
+			speedup := r.collect_repetitions(obs).get_mean(metric_speedup)
+			if speedup > 0 {
+				points = append(points, ScalePoint{obs.count_tasks(), speedup})
+			}
+		}
+	}
+
+	return points
+}
+
+// The first observation of n_tasks registered since first_idx, nil if there is none
+func (r Report) find_observation(first_idx, n_tasks int) *Observation {
+
+	for idx := first_idx; idx < r.count_observations(); idx++ {
+		if r.observations[idx].count_tasks() == n_tasks {
+			return r.get_observation(idx)
+		}
+	}
+
+	return nil
+}
+
+func (r Report) collect_repetitions(obs *Observation) Repetitions {
+
+	reps := create_repetitions()
+
+	for _, other := range r.observations {
+		if other.is_same_experiment(obs) && other.count_tasks() == obs.count_tasks() {
+			reps.add(other)
+		}
+	}
+
+	return reps
+}
+
+// The baseline is the mean over all repetitions of the first observation,
+// per cycle, so that tasks of different sizes get their own ideal durations
+func (r Report) get_baseline_ms_per_cycle(obs *Observation) float64 {
+	return r.collect_repetitions(r.find_baseline(obs)).get_mean(
+		func(o *Observation) float64 { return o.get_ms_per_cycle() })
+}
+
+// The first observation of every experiment, which its metrics are relative to
+func (r Report) collect_baselines() []*Observation {
+
+	baselines := []*Observation{}
+
+	for idx := range r.observations {
+		if obs := r.get_observation(idx); r.find_baseline(obs) == obs {
+			baselines = append(baselines, obs)
+		}
+	}
+
+	return baselines
+}
+
+// The last observation with the most tasks of the experiment
+func (r Report) find_largest_observation(baseline *Observation) *Observation {
+
+	var largest *Observation = nil
+
+	for idx := range r.observations {
+		obs := r.get_observation(idx)
+		if obs.is_same_experiment(baseline) && (largest == nil || obs.count_tasks() >= largest.count_tasks()) {
+			largest = obs
+		}
+	}
+
+	return largest
+}
+
+func (r Report) get_baseline_cycles_per_sec(obs *Observation) float64 {
+	return r.collect_repetitions(r.find_baseline(obs)).get_mean(metric_cycles_per_sec)
+}
+
+func (r *Report) recalc_concurrency(obs *Observation) {
+
+	ms_per_cycle := r.get_baseline_ms_per_cycle(obs)
+	baseline_cycles_per_sec := r.get_baseline_cycles_per_sec(obs)
+
+	for idx := range r.observations {
+		if r.observations[idx].is_same_experiment(obs) {
+			r.observations[idx].set_baseline_ms_per_cycle(ms_per_cycle)
+			r.observations[idx].calc_concurrency_cost()
+			r.observations[idx].calc_concurrency_profit()
+			r.observations[idx].calc_speedup()
+			r.observations[idx].calc_throughput_speedup(baseline_cycles_per_sec)
+		}
+	}
+}
+
+func (r *Report) register_observation(obs Observation) {
+
+	obs.recalc_tasks_relative_earliest_start()
+
+	r.observations = append(r.observations, obs)
+
+	r.recalc_concurrency(r.get_last_observation())
+}
+
+func (r Report) get_observation(idx int) *Observation {
+	return &(r.observations[idx])
+}
+
+func (r Report) get_last_observation() *Observation {
+	return r.get_observation(r.count_observations() - 1)
+}
+
+func create_report(gomaxprocs int) Report {
+	return Report{[]Observation{}, gomaxprocs, []ColdStart{}, 0, 0, 0, "", 0, 0, 0, time.Time{}, "", MachineProfile{}, 0, RunLabels{}}
+}
+
+// Comparing a cold start with a warm one
+
+// The same single-task observation made twice in a row, before anything
+// else of the experiment runs
+type ColdStart struct {
+	cold Observation
+	warm Observation
+}
+
+func (c ColdStart) get_workload_name() string {
+	return c.cold.get_workload_name()
+}
+
+func (c ColdStart) get_executor_name() string {
+	return c.cold.get_executor_name()
+}
+
+func (c ColdStart) get_cold_duration() time.Duration {
+	return c.cold.get_total_duration()
+}
+
+func (c ColdStart) get_warm_duration() time.Duration {
+	return c.warm.get_total_duration()
+}
+
+func (c ColdStart) get_difference() time.Duration {
+	return c.get_cold_duration() - c.get_warm_duration()
+}
+
+// Relative to the warm duration, in percent
+func (c ColdStart) get_relative_difference() float64 {
+	if c.get_warm_duration() > 0 {
+		return float64(c.get_difference()) * 100 / float64(c.get_warm_duration())
+	} else {
+		return 0
+	}
+}
+
+func create_cold_start(cold, warm Observation) ColdStart {
+	return ColdStart{cold, warm}
+}
+
+// Aggregating repetitions
+
+type Aggregate = int
+
+const (
+	AGG_Mean = iota
+	AGG_Median
+)
+
+func format_aggregate(aggregate Aggregate) string {
+	switch aggregate {
+	case AGG_Median:
+		return "median"
+	default:
+		return "mean"
+	}
+}
+
+func parse_aggregate(s string) (Aggregate, bool) {
+	switch s {
+	case "mean":
+		return AGG_Mean, true
+	case "median":
+		return AGG_Median, true
+	default:
+		return AGG_Mean, false
+	}
+}
+
+// Methods of confidence intervals: the normal theory one with Student's t,
+// or the percentile bootstrap, which does not assume any distribution
+type ConfidenceMethod = int
+
+const (
+	CI_T = iota
+	CI_Bootstrap
+)
+
+func format_confidence_method(method ConfidenceMethod) string {
+	switch method {
+	case CI_Bootstrap:
+		return "bootstrap"
+	default:
+		return "t"
+	}
+}
+
+func parse_confidence_method(s string) (ConfidenceMethod, bool) {
+	switch s {
+	case "t":
+		return CI_T, true
+	case "bootstrap":
+		return CI_Bootstrap, true
+	default:
+		return CI_T, false
+	}
+}
+
+const BOOTSTRAP_RESAMPLES = 1000
+
+// Bounds of the 95% percentile bootstrap interval of the statistic; resampling
+// with the seed of the report repeats the bounds along with the run on --seed
+func bootstrap_interval_of(values []float64, statistic func([]float64) float64, seed int64) (float64, float64) {
+
+	if len(values) < 2 {
+		if len(values) == 1 {
+			return values[0], values[0]
+		}
+		return 0, 0
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	resample := make([]float64, len(values))
+	estimates := make([]float64, BOOTSTRAP_RESAMPLES)
+
+	for resample_idx := range estimates {
+		for idx := range resample {
+			resample[idx] = values[rng.Intn(len(values))]
+		}
+		estimates[resample_idx] = statistic(resample)
+	}
+
+	return percentile_of(estimates, 2.5), percentile_of(estimates, 97.5)
+}
+
+func p95_of(values []float64) float64 {
+	return percentile_of(values, 95)
+}
+
+func mean_of(values []float64) float64 {
+
+	sum := 0.0
+
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values))
+}
+
+func median_of(values []float64) float64 {
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	middle := len(sorted) / 2
+
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	} else {
+		return (sorted[middle-1] + sorted[middle]) / 2
+	}
+}
+
+// Two-sided 97.5% quantiles of Student's t distribution for 1 to 30 degrees of freedom
+var STUDENT_T_975 = []float64{
+	12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+func student_t_975(degrees int) float64 {
+	if degrees <= len(STUDENT_T_975) {
+		return STUDENT_T_975[degrees-1]
+	} else {
+		return 1.96
+	}
+}
+
+// Regularized incomplete beta function I_x(a, b), evaluated
+// with the continued fraction of Numerical Recipes
+func incomplete_beta(x, a, b float64) float64 {
+
+	if x <= 0 {
+		return 0
+	} else if x >= 1 {
+		return 1
+	}
+
+	lgamma_ab, _ := math.Lgamma(a + b)
+	lgamma_a, _ := math.Lgamma(a)
+	lgamma_b, _ := math.Lgamma(b)
+	front := math.Exp(lgamma_ab - lgamma_a - lgamma_b + a*math.Log(x) + b*math.Log(1-x))
+
+	// The fraction converges fast only below the mean of the distribution
+	if x < (a+1)/(a+b+2) {
+		return front * beta_continued_fraction(x, a, b) / a
+	} else {
+		return 1 - front*beta_continued_fraction(1-x, b, a)/b
+	}
+}
+
+func beta_continued_fraction(x, a, b float64) float64 {
+
+	const max_iterations = 200
+	const epsilon = 1e-12
+	const tiny = 1e-300
+
+	c := 1.0
+	d := 1 - (a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	fraction := d
+
+	for m := 1; m <= max_iterations; m++ {
+
+		fm := float64(m)
+
+		for step := 0; step < 2; step++ {
+
+			var numerator float64
+			if step == 0 {
+				numerator = fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+			} else {
+				numerator = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+			}
+
+			d = 1 + numerator*d
+			if math.Abs(d) < tiny {
+				d = tiny
+			}
+			c = 1 + numerator/c
+			if math.Abs(c) < tiny {
+				c = tiny
+			}
+			d = 1 / d
+			fraction *= d * c
+
+			if step == 1 && math.Abs(d*c-1) < epsilon {
+				return fraction
+			}
+		}
+	}
+
+	return fraction
+}
+
+// Two-sided p-value of a t statistic with the degrees of freedom
+func student_t_p_value(t, degrees float64) float64 {
+	return incomplete_beta(degrees/(degrees+t*t), degrees/2, 0.5)
+}
+
+// Welch's t-test for the means of two samples with unequal variances,
+// returns the t statistic, the Welch-Satterthwaite degrees of freedom,
+// 0 when there are none to speak of, and the two-sided p-value
+func welch_t_test(a, b []float64) (float64, float64, float64) {
+
+	n_a := float64(len(a))
+	n_b := float64(len(b))
+
+	if len(a) < 2 || len(b) < 2 {
+		return 0, 0, 1
+	}
+
+	var_a := standard_deviation_of(a) * standard_deviation_of(a) / n_a
+	var_b := standard_deviation_of(b) * standard_deviation_of(b) / n_b
+	difference := mean_of(a) - mean_of(b)
+
+	if var_a+var_b == 0 {
+		if difference == 0 {
+			return 0, 0, 1
+		} else {
+			return math.Copysign(math.Inf(1), difference), 0, 0
+		}
+	}
+
+	t := difference / math.Sqrt(var_a+var_b)
+	degrees := (var_a + var_b) * (var_a + var_b) /
+		(var_a*var_a/(n_a-1) + var_b*var_b/(n_b-1))
+
+	return t, degrees, student_t_p_value(t, degrees)
+}
+
+// Half-width of the 95% confidence interval for the mean, 0 for a single value
+func confidence_of(values []float64) float64 {
+
+	n := len(values)
+
+	if n < 2 {
+		return 0
+	}
+
+	return student_t_975(n-1) * standard_deviation_of(values) / math.Sqrt(float64(n))
+}
+
+// Linear interpolation between the closest ranks, percentile from 0 to 100
+func percentile_of(values []float64, percentile float64) float64 {
+
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	rank := percentile / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+func standard_deviation_of(values []float64) float64 {
+
+	if len(values) < 2 {
+		return 0
+	}
+
+	mean := mean_of(values)
+	dispersion := 0.0
+
+	for _, value := range values {
+		dispersion += (value - mean) * (value - mean)
+	}
+
+	return math.Sqrt(dispersion / float64(len(values)-1))
+}
+
+// Central moment of the order
+func central_moment_of(values []float64, order int) float64 {
+
+	mean := mean_of(values)
+	sum := 0.0
+
+	for _, value := range values {
+		sum += math.Pow(value-mean, float64(order))
+	}
+
+	return sum / float64(len(values))
+}
+
+// Positive when a long tail stretches towards larger values, 0 for fewer than 3 values
+func skewness_of(values []float64) float64 {
+
+	if len(values) < 3 {
+		return 0
+	}
+
+	variance := central_moment_of(values, 2)
+
+	if variance > 0 {
+		return central_moment_of(values, 3) / math.Pow(variance, 1.5)
+	} else {
+		return 0
+	}
+}
+
+// Excess kurtosis, positive when outlying values are more frequent
+// than under the normal distribution, 0 for fewer than 4 values
+func kurtosis_of(values []float64) float64 {
+
+	if len(values) < 4 {
+		return 0
+	}
+
+	variance := central_moment_of(values, 2)
+
+	if variance > 0 {
+		return central_moment_of(values, 4)/(variance*variance) - 3
+	} else {
+		return 0
+	}
+}
+
+// Histograms of task durations
+
+// Bucket idx holds durations above bounds[idx-1] up to bounds[idx],
+// the last bucket holds durations above all bounds
+type Histogram struct {
+	bounds []time.Duration
+	counts []int64
+}
+
+// Safe to call from concurrent tasks
+func (h *Histogram) record(duration time.Duration) {
+	bucket_idx := sort.Search(len(h.bounds), func(idx int) bool {
+		return h.bounds[idx] >= duration
+	})
+	atomic.AddInt64(&h.counts[bucket_idx], 1)
+}
+
+func (h *Histogram) clone() *Histogram {
+
+	copied := create_histogram(h.bounds)
+
+	for bucket_idx := range h.counts {
+		copied.counts[bucket_idx] = h.get_bucket_count(bucket_idx)
+	}
+
+	return copied
+}
+
+func (h Histogram) count_buckets() int {
+	return len(h.counts)
+}
+
+func (h Histogram) get_bucket_count(bucket_idx int) int64 {
+	return atomic.LoadInt64(&h.counts[bucket_idx])
+}
+
+func (h Histogram) get_bucket_from(bucket_idx int) time.Duration {
+	if bucket_idx > 0 {
+		return h.bounds[bucket_idx-1]
+	} else {
+		return 0
+	}
+}
+
+func (h Histogram) format_bucket_to(bucket_idx int) string {
+	if bucket_idx < len(h.bounds) {
+		return strconv.FormatInt(h.bounds[bucket_idx].Milliseconds(), 10)
+	} else {
+		return "+Inf"
+	}
+}
+
+func create_histogram(bounds []time.Duration) *Histogram {
+	return &Histogram{bounds, make([]int64, len(bounds)+1)}
+}
+
+// Bounds in the manner of HDR histograms: every power of two split
+// into four sub-buckets, so a bucket is at most a quarter as wide as its values
+func create_log_bounds(max_bound time.Duration) []time.Duration {
+
+	bounds := []time.Duration{1 * time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond, 4 * time.Millisecond}
+
+	for power := 4 * time.Millisecond; power < max_bound; power *= 2 {
+		for sub_idx := time.Duration(1); sub_idx <= 4; sub_idx++ {
+			bounds = append(bounds, power+sub_idx*power/4)
+		}
+	}
+
+	return bounds
+}
+
+// none, log, or a list of increasing upper bounds in ms
+func parse_histogram_bounds(s string) ([]time.Duration, bool) {
+
+	switch s {
+	case "none":
+		return nil, true
+	case "log":
+		return create_log_bounds((1 << 20) * time.Millisecond), true
+	}
+
+	bounds := []time.Duration{}
+
+	for _, item := range strings.Split(s, ",") {
+		bound := from_ms(parse_int(item))
+		if bound <= 0 || (len(bounds) > 0 && bound <= bounds[len(bounds)-1]) {
+			return nil, false
+		}
+		bounds = append(bounds, bound)
+	}
+
+	return bounds, true
+}
+
+// Detecting outliers
+
+type OutlierRule = int
+
+const (
+	OR_None = iota
+	OR_IQR
+	OR_MAD
+)
+
+func parse_outlier_rule(s string) (OutlierRule, bool) {
+	switch s {
+	case "none":
+		return OR_None, true
+	case "iqr":
+		return OR_IQR, true
+	case "mad":
+		return OR_MAD, true
+	default:
+		return OR_None, false
+	}
+}
+
+func format_outlier_rule(rule OutlierRule) string {
+	switch rule {
+	case OR_IQR:
+		return "iqr"
+	case OR_MAD:
+		return "mad"
+	default:
+		return "none"
+	}
+}
+
+// Values outside the bounds are outliers: by IQR, beyond 1.5 interquartile ranges
+// from the quartiles, by MAD, beyond 3 scaled median absolute deviations from the median
+func find_outlier_bounds(values []float64, rule OutlierRule) (float64, float64) {
+
+	if len(values) == 0 {
+		return math.Inf(-1), math.Inf(1)
+	}
+
+	switch rule {
+	case OR_IQR:
+		q1 := percentile_of(values, 25)
+		q3 := percentile_of(values, 75)
+		return q1 - 1.5*(q3-q1), q3 + 1.5*(q3-q1)
+	case OR_MAD:
+		median := median_of(values)
+		deviations := []float64{}
+		for _, value := range values {
+			deviations = append(deviations, math.Abs(value-median))
+		}
+		mad := 1.4826 * median_of(deviations)
+		if mad == 0 {
+			return math.Inf(-1), math.Inf(1)
+		}
+		return median - 3*mad, median + 3*mad
+	default:
+		return math.Inf(-1), math.Inf(1)
+	}
+}
+
+type Metric = func(*Observation) float64
+
+// Repetitions of the same observation, that is, the same experiment at the same number of tasks
+type Repetitions struct {
+	observations []Observation
+}
+
+func (r *Repetitions) add(obs Observation) {
+	r.observations = append(r.observations, obs)
+}
+
+func (r Repetitions) count_reps() int {
+	return len(r.observations)
+}
+
+func (r Repetitions) get_first() *Observation {
+	return &(r.observations[0])
+}
+
+func (r Repetitions) collect(metric Metric) []float64 {
+
+	values := []float64{}
+
+	for idx := range r.observations {
+		values = append(values, metric(&(r.observations[idx])))
+	}
+
+	return values
+}
+
+func (r Repetitions) get_mean(metric Metric) float64 {
+	return mean_of(r.collect(metric))
+}
+
+func (r Repetitions) get_median(metric Metric) float64 {
+	return median_of(r.collect(metric))
+}
+
+// The difference between the largest and the smallest value of the metric
+func (r Repetitions) get_range(metric Metric) float64 {
+
+	values := r.collect(metric)
+	sort.Float64s(values)
+
+	return values[len(values)-1] - values[0]
+}
+
+// The range relative to the mean, 0 if the mean is 0
+func (r Repetitions) get_relative_spread(metric Metric) float64 {
+
+	if mean := r.get_mean(metric); mean != 0 {
+		return r.get_range(metric) / math.Abs(mean)
+	} else {
+		return 0
+	}
+}
+
+func (r Repetitions) get_confidence(metric Metric) float64 {
+	return confidence_of(r.collect(metric))
+}
+
+// Durations of measured tasks of all repetitions taken together
+func (r Repetitions) collect_task_durations() []float64 {
+
+	durations := []float64{}
+
+	for idx := range r.observations {
+		durations = append(durations, r.observations[idx].collect_measured_durations()...)
+	}
+
+	return durations
+}
+
+// Resamples the durations of single tasks, so it works even without repetitions
+func (r Repetitions) get_bootstrap_task_interval(statistic func([]float64) float64, seed int64) (float64, float64) {
+	return bootstrap_interval_of(r.collect_task_durations(), statistic, seed)
+}
+
+// Resamples the metric of repetitions, so a single repetition gives a point
+func (r Repetitions) get_bootstrap_interval(metric Metric, seed int64) (float64, float64) {
+	return bootstrap_interval_of(r.collect(metric), mean_of, seed)
+}
+
+func (r Repetitions) get_aggregate(metric Metric, aggregate Aggregate) float64 {
+	switch aggregate {
+	case AGG_Median:
+		return r.get_median(metric)
+	default:
+		return r.get_mean(metric)
+	}
+}
+
+func create_repetitions() Repetitions {
+	return Repetitions{[]Observation{}}
+}
+
+func metric_mean_task_duration(o *Observation) float64 {
+	return to_ms(o.get_mean_task_duration())
+}
+
+func metric_standard_deviation(o *Observation) float64 {
+	return o.get_standard_deviation()
+}
+
+func metric_total_duration(o *Observation) float64 {
+	return to_ms(o.get_total_duration())
+}
+
+func metric_concurrency_cost(o *Observation) float64 {
+	return o.get_concurrency_cost()
+}
+
+func metric_concurrency_profit(o *Observation) float64 {
+	return o.get_concurrency_profit()
+}
+
+func metric_speedup(o *Observation) float64 {
+	return o.get_speedup()
+}
+
+func metric_efficiency(o *Observation) float64 {
+	return o.get_efficiency()
+}
+
+func metric_utilization(o *Observation) float64 {
+	return o.get_utilization()
+}
+
+func metric_completed_runs(o *Observation) float64 {
+	return float64(o.count_completed_runs())
+}
+
+func metric_cycles_per_sec(o *Observation) float64 {
+	return o.get_cycles_per_sec()
+}
+
+func metric_throughput_speedup(o *Observation) float64 {
+	return o.get_throughput_speedup()
+}
+
+// Fitting scalability models
+
+// Speedup observed at a number of concurrent tasks
+type ScalePoint struct {
+	n_tasks int
+	speedup float64
+}
+
+// Amdahl's law S(N) = 1 / (s + (1 - s) / N) is linear in the serial fraction s
+// once rewritten as 1/S - 1/N = s (1 - 1/N), so least squares give s directly
+func fit_amdahl(points []ScalePoint) float64 {
+
+	sum_xy := 0.0
+	sum_xx := 0.0
+
+	for _, point := range points {
+		x := 1 - 1/float64(point.n_tasks)
+		y := 1/point.speedup - 1/float64(point.n_tasks)
+		sum_xy += x * y
+		sum_xx += x * x
+	}
+
+	if sum_xx == 0 {
+		return 0
+	}
+
+	return math.Min(1, math.Max(0, sum_xy/sum_xx))
+}
+
+// The Universal Scalability Law S(N) = N / (1 + σ (N - 1) + κ N (N - 1)) is linear
+// in the contention σ and the coherence κ once rewritten as N/S - 1 = σ (N - 1) + κ N (N - 1);
+// a coefficient that comes out negative is dropped and the other one refitted alone;
+// the contention is a share of the work, so it stays within 0 to 1
+func fit_usl(points []ScalePoint) (float64, float64) {
+
+	var saa, sab, sbb, say, sby float64
+
+	for _, point := range points {
+		n := float64(point.n_tasks)
+		a := n - 1
+		b := n * (n - 1)
+		y := n/point.speedup - 1
+		saa += a * a
+		sab += a * b
+		sbb += b * b
+		say += a * y
+		sby += b * y
+	}
+
+	det := saa*sbb - sab*sab
+
+	if det > 0 {
+		contention := (say*sbb - sby*sab) / det
+		coherence := (sby*saa - say*sab) / det
+		if contention >= 0 && contention <= 1 && coherence >= 0 {
+			return contention, coherence
+		}
+	}
+
+	contention := 0.0
+	coherence := 0.0
+
+	if saa > 0 {
+		contention = math.Min(1, math.Max(0, say/saa))
+	}
+
+	if contention == 0 && sbb > 0 {
+		coherence = math.Max(0, sby/sbb)
+	}
+
+	return contention, coherence
+}
+
+// The number of tasks at which the USL speedup peaks, +Inf without coherence costs;
+// one task is best when the contention takes up all the work
+func find_usl_optimum(contention, coherence float64) float64 {
+	if contention >= 1 {
+		return 1
+	} else if coherence > 0 {
+		return math.Max(1, math.Sqrt((1-contention)/coherence))
+	} else {
+		return math.Inf(1)
+	}
+}
+
+// Performing observations
+
+type Executor = int
+
+const (
+	EX_Batch = iota
+	EX_Pool
+	EX_Semaphore
+	EX_OpenLoop
+	EX_RampUp
+	EX_Overlap
+	EX_Process
+	EX_Graph
+	EX_Chunked
+)
+
+func format_executor(executor Executor) string {
+	switch executor {
+	case EX_Pool:
+		return "pool"
+	case EX_Semaphore:
+		return "semaphore"
+	case EX_OpenLoop:
+		return "open"
+	case EX_RampUp:
+		return "ramp"
+	case EX_Overlap:
+		return "overlap"
+	case EX_Process:
+		return "process"
+	case EX_Graph:
+		return "graph"
+	case EX_Chunked:
+		return "chunked"
+	default:
+		return "batch"
+	}
+}
+
+// Names parse_executors accepts, offered by shell completion
+var EXECUTOR_NAMES = []string{
+	"batch", "pool", "semaphore", "open", "ramp", "overlap", "series",
+	"process", "scaling", "graph", "chunked", "all",
+}
+
+func parse_executors(s string) ([]Executor, bool) {
+	switch s {
+	case "batch":
+		return []Executor{EX_Batch}, true
+	case "pool":
+		return []Executor{EX_Pool}, true
+	case "semaphore":
+		return []Executor{EX_Semaphore}, true
+	case "open":
+		return []Executor{EX_OpenLoop}, true
+	case "ramp":
+		return []Executor{EX_RampUp}, true
+	case "overlap":
+		return []Executor{EX_Overlap}, true
+	case "series":
+		return []Executor{EX_Batch, EX_Overlap}, true
+	case "process":
+		return []Executor{EX_Process}, true
+	case "scaling":
+		return []Executor{EX_Batch, EX_Process}, true
+	case "graph":
+		return []Executor{EX_Graph}, true
+	case "chunked":
+		return []Executor{EX_Chunked}, true
+	case "all":
+		return []Executor{EX_Batch, EX_Overlap, EX_Pool, EX_Semaphore}, true
+	default:
+		return []Executor{}, false
+	}
+}
+
+type Setup struct {
+	n_cycles       int
+	series_size    int
+	sizing         TaskSizing
+	workload       Workload
+	executor       Executor
+	fail_fast      bool
+	arrival_rate   float64
+	n_warmups      int
+	n_reps         int
+	aggregate      Aggregate
+	thread_locked  bool
+	launch_order   LaunchOrder
+	stagger        time.Duration
+	ramp_period    time.Duration
+	bound          time.Duration
+	n_noise        int
+	child_options  []string
+	graph_shape    GraphShape
+	graph_width    int
+	launch_rate    float64
+	launch_burst   int
+	chunk_size     int
+	task_timeout   time.Duration
+	outlier_rule   OutlierRule
+	max_cv         float64
+	cold_warm      bool
+	histogram      []time.Duration
+	ci_method      ConfidenceMethod
+	streaming      bool
+	cpu_timed      bool
+	tracer         *Tracer
+	perf_counted   bool
+	sampling       time.Duration
+	task_allocs    bool
+	progress       *Progress
+	checkpoint     *Checkpoint
+	swept          bool
+	experiment_idx int
+	reporters      Reporters
+}
+
+// Experiments of a batch count from 1, which the title then names
+func (s Setup) get_experiment_idx() int {
+	return s.experiment_idx
+}
+
+func (s *Setup) set_experiment_idx(experiment_idx int) {
+	s.experiment_idx = experiment_idx
+}
+
+// One of several series sizes or cycles, which the title then names
+func (s Setup) is_swept() bool {
+	return s.swept
+}
+
+func (s *Setup) set_swept(swept bool) {
+	s.swept = swept
+}
+
+func (s Setup) get_checkpoint() *Checkpoint {
+	return s.checkpoint
+}
+
+func (s *Setup) set_checkpoint(checkpoint *Checkpoint) {
+	s.checkpoint = checkpoint
+}
+
+func (s Setup) get_progress() *Progress {
+	return s.progress
+}
+
+func (s *Setup) set_progress(progress *Progress) {
+	s.progress = progress
+}
+
+func (s Setup) are_allocs_counted() bool {
+	return s.task_allocs
+}
+
+func (s *Setup) set_allocs_counted(task_allocs bool) {
+	s.task_allocs = task_allocs
+}
+
+// 0 when goroutines are not sampled
+func (s Setup) get_sampling_interval() time.Duration {
+	return s.sampling
+}
+
+func (s *Setup) set_sampling_interval(sampling time.Duration) {
+	s.sampling = sampling
+}
+
+func (s Setup) is_perf_counted() bool {
+	return s.perf_counted
+}
+
+func (s *Setup) set_perf_counted(perf_counted bool) {
+	s.perf_counted = perf_counted
+}
+
+func (s Setup) get_reporters() Reporters {
+	return s.reporters
+}
+
+func (s *Setup) set_reporters(reporters Reporters) {
+	s.reporters = reporters
+}
+
+func (s Setup) get_tracer() *Tracer {
+	return s.tracer
+}
+
+func (s *Setup) set_tracer(tracer *Tracer) {
+	s.tracer = tracer
+}
+
+// Tasks locked to OS threads while the CPU time of their thread is read
+func (s Setup) is_cpu_timed() bool {
+	return s.cpu_timed
+}
+
+func (s *Setup) set_cpu_timed(cpu_timed bool) {
+	s.cpu_timed = cpu_timed
+}
+
+func (s Setup) is_streaming() bool {
+	return s.streaming
+}
+
+func (s *Setup) set_streaming(streaming bool) {
+	s.streaming = streaming
+}
+
+func (s Setup) get_ci_method() ConfidenceMethod {
+	return s.ci_method
+}
+
+func (s *Setup) set_ci_method(ci_method ConfidenceMethod) {
+	s.ci_method = ci_method
+}
+
+func (s Setup) get_histogram_bounds() []time.Duration {
+	return s.histogram
+}
+
+func (s *Setup) set_histogram_bounds(bounds []time.Duration) {
+	s.histogram = bounds
+}
+
+func (s Setup) is_cold_warm() bool {
+	return s.cold_warm
+}
+
+func (s *Setup) set_cold_warm(cold_warm bool) {
+	s.cold_warm = cold_warm
+}
+
+func (s Setup) get_max_cv() float64 {
+	return s.max_cv
+}
+
+func (s *Setup) set_max_cv(max_cv float64) {
+	s.max_cv = max_cv
+}
+
+func (s Setup) get_outlier_rule() OutlierRule {
+	return s.outlier_rule
+}
+
+func (s *Setup) set_outlier_rule(outlier_rule OutlierRule) {
+	s.outlier_rule = outlier_rule
+}
+
+func (s Setup) get_task_timeout() time.Duration {
+	return s.task_timeout
+}
+
+func (s *Setup) set_task_timeout(task_timeout time.Duration) {
+	s.task_timeout = task_timeout
+}
+
+// Chunked executors of different chunk sizes are different experiments
+func (s Setup) get_executor_name() string {
+	if s.get_executor() == EX_Chunked {
+		return fmt.Sprintf("%s-%d", format_executor(s.get_executor()), s.get_chunk_size())
+	} else {
+		return format_executor(s.get_executor())
+	}
+}
+
+func (s Setup) get_chunk_size() int {
+	return s.chunk_size
+}
+
+func (s *Setup) set_chunk_size(chunk_size int) {
+	s.chunk_size = chunk_size
+}
+
+func (s Setup) get_n_cycles() int {
+	return s.n_cycles
+}
+
+func (s Setup) get_series_size() int {
+	return s.series_size
+}
+
+func (s Setup) get_sizing() TaskSizing {
+	return s.sizing
+}
+
+func (s Setup) get_workload() Workload {
+	return s.workload
+}
+
+func (s Setup) get_executor() Executor {
+	return s.executor
+}
+
+func (s *Setup) set_executor(executor Executor) {
+	s.executor = executor
+}
+
+func (s Setup) is_fail_fast() bool {
+	return s.fail_fast
+}
+
+func (s Setup) get_arrival_rate() float64 {
+	return s.arrival_rate
+}
+
+func (s Setup) count_warmups() int {
+	return s.n_warmups
+}
+
+func (s Setup) count_reps() int {
+	return s.n_reps
+}
+
+func (s Setup) get_aggregate() Aggregate {
+	return s.aggregate
+}
+
+func (s Setup) is_thread_locked() bool {
+	return s.thread_locked
+}
+
+func (s *Setup) set_thread_locked(thread_locked bool) {
+	s.thread_locked = thread_locked
+}
+
+func (s Setup) get_launch_order() LaunchOrder {
+	return s.launch_order
+}
+
+func (s Setup) get_stagger() time.Duration {
+	return s.stagger
+}
+
+func (s Setup) get_bound() time.Duration {
+	return s.bound
+}
+
+func (s Setup) is_bounded() bool {
+	return s.get_bound() > 0
+}
+
+func (s *Setup) set_bound(bound time.Duration) {
+	s.bound = bound
+}
+
+func (s Setup) get_n_noise() int {
+	return s.n_noise
+}
+
+func (s *Setup) set_n_noise(n_noise int) {
+	s.n_noise = n_noise
+}
+
+func (s Setup) get_launch_rate() float64 {
+	return s.launch_rate
+}
+
+func (s Setup) get_launch_burst() int {
+	return s.launch_burst
+}
+
+func (s *Setup) set_launch_rate(launch_rate float64, launch_burst int) {
+	s.launch_rate = launch_rate
+	s.launch_burst = launch_burst
+}
+
+func (s Setup) get_graph_shape() GraphShape {
+	return s.graph_shape
+}
+
+func (s Setup) get_graph_width() int {
+	return s.graph_width
+}
+
+func (s *Setup) set_graph(graph_shape GraphShape, graph_width int) {
+	s.graph_shape = graph_shape
+	s.graph_width = graph_width
+}
+
+func (s Setup) get_child_options() []string {
+	return s.child_options
+}
+
+func (s *Setup) set_child_options(child_options []string) {
+	s.child_options = child_options
+}
+
+func (s Setup) get_ramp_period() time.Duration {
+	return s.ramp_period
+}
+
+func (s *Setup) set_ramp_period(ramp_period time.Duration) {
+	s.ramp_period = ramp_period
+}
+
+func (s *Setup) set_launching(launch_order LaunchOrder, stagger time.Duration) {
+	s.launch_order = launch_order
+	s.stagger = stagger
+}
+
+func create_setup(
+	n_cycles, series_size int,
+	sizing TaskSizing,
+	workload Workload,
+	executor Executor,
+	fail_fast bool,
+	arrival_rate float64,
+	n_warmups, n_reps int,
+	aggregate Aggregate) Setup {
+
+	return Setup{
+		n_cycles,
+		series_size,
+		sizing,
+		workload,
+		executor,
+		fail_fast,
+		arrival_rate,
+		n_warmups,
+		n_reps,
+		aggregate,
+		false,
+		LO_Forward,
+		0,
+		0,
+		0,
+		0,
+		nil,
+		GS_Fan,
+		series_size,
+		0,
+		1,
+		1,
+		0,
+		OR_None,
+		0,
+		false,
+		nil,
+		CI_T,
+		false,
+		false,
+		nil,
+		false,
+		0,
+		false,
+		nil,
+		nil,
+		false,
+		0,
+		nil}
+}
+
+// Runs goroutines the way errgroup does: remembers the first failure
+// and, when failing fast, cancels the context of the rest
+type TaskGroup struct {
+	syncler   sync.WaitGroup
+	lock      sync.Mutex
+	err       error
+	fail_fast bool
+	cancel    context.CancelFunc
+}
+
+func (g *TaskGroup) fail(err error) {
+
+	if err == nil || is_cancellation(err) {
+		return
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+
+	if g.err == nil {
+		g.err = err
+		if g.fail_fast {
+			g.cancel()
+		}
+	}
+}
+
+func (g *TaskGroup) go_task(task func() error) {
+
+	g.syncler.Add(1)
+
+	go func() {
+		g.fail(task())
+		g.syncler.Done()
+	}()
+}
+
+func (g *TaskGroup) wait() error {
+	g.syncler.Wait()
+	return g.err
+}
+
+func (g *TaskGroup) release() {
+	g.cancel()
+}
+
+func create_task_group(ctx context.Context, fail_fast bool) (*TaskGroup, context.Context) {
+	group_ctx, cancel := context.WithCancel(ctx)
+	return &TaskGroup{fail_fast: fail_fast, cancel: cancel}, group_ctx
+}
+
+func count_series(n_tasks, series_size int) int {
+
+	n_series := n_tasks / series_size
+
+	if series_size*n_series < n_tasks {
+		n_series++
+	}
+
+	return n_series
+}
+
+func draw_tasks_cycles(n_tasks, n_cycles int, sizing TaskSizing) []int {
+
+	tasks_cycles := make([]int, n_tasks)
+
+	for task_idx := range tasks_cycles {
+		tasks_cycles[task_idx] = sizing.draw_n_cycles(n_cycles)
+	}
+
+	return tasks_cycles
+}
+
+type LaunchOrder = int
+
+const (
+	LO_Forward = iota
+	LO_Reverse
+	LO_Shuffle
+)
+
+func parse_launch_order(s string) (LaunchOrder, bool) {
+	switch s {
+	case "forward":
+		return LO_Forward, true
+	case "reverse":
+		return LO_Reverse, true
+	case "shuffle":
+		return LO_Shuffle, true
+	default:
+		return LO_Forward, false
+	}
+}
+
+func format_launch_order(order LaunchOrder) string {
+	switch order {
+	case LO_Reverse:
+		return "reverse"
+	case LO_Shuffle:
+		return "shuffle"
+	default:
+		return "forward"
+	}
+}
+
+// Lets through rate launches per second on average and at most capacity at once
+type TokenBucket struct {
+	rate     float64
+	capacity float64
+	tokens   float64
+	refilled time.Time
+}
+
+func (b *TokenBucket) refill() {
+
+	now := time.Now()
+
+	b.tokens = math.Min(b.capacity, b.tokens+now.Sub(b.refilled).Seconds()*b.rate)
+	b.refilled = now
+}
+
+func (b *TokenBucket) take(ctx context.Context) {
+
+	b.refill()
+
+	if b.tokens < 1 {
+
+		timer := time.NewTimer(time.Duration((1 - b.tokens) / b.rate * float64(time.Second)))
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return
+		}
+
+		b.refill()
+	}
+
+	b.tokens--
+}
+
+func create_token_bucket(rate float64, capacity int) *TokenBucket {
+	return &TokenBucket{rate, float64(capacity), float64(capacity), time.Now()}
+}
+
+// Decides in which order and with which pauses an executor launches tasks
+type Launcher struct {
+	order   []int
+	stagger time.Duration
+	bucket  *TokenBucket
+}
+
+func (l Launcher) count_tasks() int {
+	return len(l.order)
+}
+
+func (l Launcher) get_task_idx(position int) int {
+	return l.order[position]
+}
+
+func (l Launcher) pause(ctx context.Context, position int) {
+
+	if l.bucket != nil {
+		l.bucket.take(ctx)
+	}
+
+	if position == 0 || l.stagger <= 0 {
+		return
+	}
+
+	timer := time.NewTimer(time.Duration(RANDOM.int63n(int64(l.stagger) + 1)))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// A rate of 0 launches tasks without a limit
+func create_launcher(n_tasks int, launch_order LaunchOrder, stagger time.Duration, rate float64, burst int) Launcher {
+
+	order := make([]int, n_tasks)
+
+	for position := range order {
+		switch launch_order {
+		case LO_Reverse:
+			order[position] = n_tasks - 1 - position
+		default:
+			order[position] = position
+		}
+	}
+
+	if launch_order == LO_Shuffle {
+		RANDOM.shuffle(n_tasks, func(i, j int) {
+			order[i], order[j] = order[j], order[i]
+		})
+	}
+
+	var bucket *TokenBucket = nil
+
+	if rate > 0 {
+		bucket = create_token_bucket(rate, burst)
+	}
+
+	return Launcher{order, stagger, bucket}
+}
+
+// Describing dependencies between tasks
+
+type GraphShape = int
+
+const (
+	GS_Fan = iota
+	GS_Layers
+)
+
+func parse_graph_shape(s string) (GraphShape, bool) {
+	switch s {
+	case "fan":
+		return GS_Fan, true
+	case "layers":
+		return GS_Layers, true
+	default:
+		return GS_Fan, false
+	}
+}
+
+func format_graph_shape(shape GraphShape) string {
+	switch shape {
+	case GS_Layers:
+		return "layers"
+	default:
+		return "fan"
+	}
+}
+
+// Tasks in layers, each task depending on every task of the previous layer.
+// Layers of the fan shape alternate between a single task and width tasks,
+// that is, fan out and fan in again; all layers of the layers shape are width wide.
+type TaskGraph struct {
+	layers [][]int
+}
+
+func (g TaskGraph) count_layers() int {
+	return len(g.layers)
+}
+
+func (g TaskGraph) get_layer(layer_idx int) []int {
+	return g.layers[layer_idx]
+}
+
+// The longest chain of dependent tasks; as every task waits
+// for the whole previous layer, it passes the longest task of each layer
+func (g TaskGraph) calc_critical_path(tasks []Task) time.Duration {
+
+	var critical_path time.Duration = 0
+
+	for _, layer := range g.layers {
+
+		var longest time.Duration = 0
+
+		for _, task_idx := range layer {
+			if tasks[task_idx].get_duration() > longest {
+				longest = tasks[task_idx].get_duration()
+			}
+		}
+
+		critical_path += longest
+	}
+
+	return critical_path
+}
+
+func create_task_graph(n_tasks int, shape GraphShape, width int) TaskGraph {
+
+	graph := TaskGraph{[][]int{}}
+
+	for task_idx := 0; task_idx < n_tasks; {
+
+		layer_width := width
+
+		if shape == GS_Fan && graph.count_layers()%2 == 0 {
+			layer_width = 1
+		}
+
+		layer := []int{}
+
+		for task_idx < n_tasks && len(layer) < layer_width {
+			layer = append(layer, task_idx)
+			task_idx++
+		}
+
+		graph.layers = append(graph.layers, layer)
+	}
+
+	return graph
+}
+
+// Launches a goroutine per task at once; each waits for the previous layer
+// of the graph, so a task is launched as soon as its dependencies are done
+func execute_graph(
+	ctx context.Context,
+	group *TaskGroup,
+	graph TaskGraph,
+	run_task func(int, time.Duration) error) {
+
+	prev_done := make(chan struct{})
+	close(prev_done)
+
+	for layer_idx := 0; layer_idx < graph.count_layers(); layer_idx++ {
+
+		layer := graph.get_layer(layer_idx)
+		done := make(chan struct{})
+		var layer_group sync.WaitGroup
+
+		for position := 0; position < len(layer); position++ {
+
+			task_idx := layer[position]
+			wait_for := prev_done
+
+			layer_group.Add(1)
+			group.go_task(func() error {
+				defer layer_group.Done()
+				select {
+				case <-wait_for:
+					return run_task(task_idx, now())
+				case <-ctx.Done():
+					return nil
+				}
+			})
+		}
+
+		go func() {
+			layer_group.Wait()
+			close(done)
+		}()
+
+		prev_done = done
+	}
+
+	group.wait()
+}
+
+// Launches a batch of series_size goroutines and waits for all of them before the next one
+func execute_batches(
+	ctx context.Context,
+	group *TaskGroup,
+	launcher Launcher,
+	series_size int,
+	run_task func(int, time.Duration) error) {
+
+	n_tasks := launcher.count_tasks()
+	n_series := count_series(n_tasks, series_size)
+	var position int = 0
+	var count_tasks_series int = 0
+
+	for series_idx := 0; series_idx < n_series && ctx.Err() == nil; series_idx++ {
+
+		count_tasks_series = 0
+
+		for position < n_tasks && count_tasks_series < series_size {
+
+			launcher.pause(ctx, position)
+
+			task_idx := launcher.get_task_idx(position)
+			launched := now()
+			group.go_task(func() error {
+				return run_task(task_idx, launched)
+			})
+
+			count_tasks_series++
+			position++
+		}
+
+		group.wait()
+	}
+}
+
+// Launches tasks series by series like execute_batches, but lets a task of
+// the next series take any slot freed in the previous one instead of waiting
+// for the whole series to finish
+func execute_overlapped(
+	ctx context.Context,
+	group *TaskGroup,
+	launcher Launcher,
+	series_size int,
+	run_task func(int, time.Duration) error) {
+
+	slots := make(chan struct{}, series_size)
+
+	for position := 0; position < launcher.count_tasks() && ctx.Err() == nil; position++ {
+
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			continue
+		}
+
+		launcher.pause(ctx, position)
+
+		task_idx := launcher.get_task_idx(position)
+		launched := now()
+		group.go_task(func() error {
+			defer func() { <-slots }()
+			return run_task(task_idx, launched)
+		})
+	}
+
+	group.wait()
+}
+
+type Submission struct {
+	task_idx int
+	moment   time.Duration
+}
+
+// Sends tasks to n_workers goroutines in chunks of chunk_size over a channel
+// buffering a chunk per worker; a worker runs the tasks of a chunk one by one
+func execute_chunked(
+	ctx context.Context,
+	group *TaskGroup,
+	launcher Launcher,
+	n_workers, chunk_size int,
+	run_task func(int, time.Duration) error) {
+
+	queue := make(chan []Submission, n_workers)
+
+	for worker_idx := 0; worker_idx < n_workers; worker_idx++ {
+		group.go_task(func() error {
+			for chunk := range queue {
+				for _, submission := range chunk {
+					group.fail(run_task(submission.task_idx, submission.moment))
+				}
+			}
+			return nil
+		})
+	}
+
+	chunk := []Submission{}
+
+	for position := 0; position < launcher.count_tasks() && ctx.Err() == nil; position++ {
+
+		launcher.pause(ctx, position)
+		chunk = append(chunk, Submission{launcher.get_task_idx(position), now()})
+
+		if len(chunk) == chunk_size || position == launcher.count_tasks()-1 {
+			queue <- chunk
+			chunk = []Submission{}
+		}
+	}
+
+	close(queue)
+
+	group.wait()
+}
+
+// Lets a fixed set of n_workers goroutines pull tasks from a channel
+func execute_pool(
+	ctx context.Context,
+	group *TaskGroup,
+	launcher Launcher,
+	n_workers int,
+	run_task func(int, time.Duration) error) {
+
+	queue := make(chan Submission)
+
+	for worker_idx := 0; worker_idx < n_workers; worker_idx++ {
+		group.go_task(func() error {
+			for submission := range queue {
+				group.fail(run_task(submission.task_idx, submission.moment))
+			}
+			return nil
+		})
+	}
+
+	for position := 0; position < launcher.count_tasks() && ctx.Err() == nil; position++ {
+		launcher.pause(ctx, position)
+		queue <- Submission{launcher.get_task_idx(position), now()}
+	}
+
+	close(queue)
+
+	group.wait()
+}
+
+// Launches all goroutines at once and lets at most n_slots of them run tasks
+func execute_semaphore(
+	ctx context.Context,
+	group *TaskGroup,
+	launcher Launcher,
+	n_slots int,
+	run_task func(int, time.Duration) error) {
+
+	semaphore := make(chan struct{}, n_slots)
+
+	for position := 0; position < launcher.count_tasks(); position++ {
+
+		launcher.pause(ctx, position)
+
+		task_idx := launcher.get_task_idx(position)
+		launched := now()
+		group.go_task(func() error {
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+			if ctx.Err() == nil {
+				return run_task(task_idx, launched)
+			} else {
+				return nil
+			}
+		})
+	}
+
+	group.wait()
+}
+
+func wait_next_arrival(ctx context.Context, arrival_rate float64) {
+
+	timer := time.NewTimer(time.Duration(RANDOM.exp_float64() / arrival_rate * float64(time.Second)))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// Queues all tasks up front and adds a worker goroutine every ramp period up to n_workers
+func execute_ramp_up(
+	ctx context.Context,
+	group *TaskGroup,
+	launcher Launcher,
+	n_workers int,
+	ramp_period time.Duration,
+	run_task func(int, time.Duration) error) {
+
+	queue := make(chan Submission, launcher.count_tasks())
+
+	for position := 0; position < launcher.count_tasks(); position++ {
+		queue <- Submission{launcher.get_task_idx(position), now()}
+	}
+
+	close(queue)
+
+	for worker_idx := 0; worker_idx < n_workers && ctx.Err() == nil; worker_idx++ {
+
+		if worker_idx > 0 {
+			block_go(ctx, int(ramp_period.Microseconds()))
+		}
+
+		group.go_task(func() error {
+			for submission := range queue {
+				group.fail(run_task(submission.task_idx, submission.moment))
+			}
+			return nil
+		})
+	}
+
+	group.wait()
+}
+
+// Lets tasks arrive as a Poisson process and queue up for n_workers goroutines
+func execute_open_loop(
+	ctx context.Context,
+	group *TaskGroup,
+	n_tasks, n_workers int,
+	arrival_rate float64,
+	run_task func(int, time.Duration) error) {
+
+	queue := make(chan Submission, n_tasks)
+
+	for worker_idx := 0; worker_idx < n_workers; worker_idx++ {
+		group.go_task(func() error {
+			for submission := range queue {
+				group.fail(run_task(submission.task_idx, submission.moment))
+			}
+			return nil
+		})
+	}
+
+	for task_idx := 0; task_idx < n_tasks && ctx.Err() == nil; task_idx++ {
+		if task_idx > 0 {
+			wait_next_arrival(ctx, arrival_rate)
+		}
+		queue <- Submission{task_idx, now()}
+	}
+
+	close(queue)
+
+	group.wait()
+}
+
+// Keeps n_goroutines busy with integer arithmetic until stopped,
+// competing with the tasks for CPUs
+func start_noise(ctx context.Context, n_goroutines int) func() {
+
+	noise_ctx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n_goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			iterate_integer(noise_ctx, RANDOM.uint64(), math.MaxInt)
+		}()
+	}
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// Counting allocations of tasks
+
+// The runtime counts allocations of the whole process, so allocations of tasks
+// running side by side mix; the counts are exact for tasks running alone
+var ALLOC_METRICS = []string{"/gc/heap/allocs:bytes", "/gc/heap/allocs:objects"}
+
+func create_alloc_samples() []metrics.Sample {
+
+	samples := make([]metrics.Sample, len(ALLOC_METRICS))
+
+	for idx, name := range ALLOC_METRICS {
+		samples[idx].Name = name
+	}
+
+	return samples
+}
+
+// Bytes and objects allocated by the process so far; the samples
+// are reused, so that reading does not allocate itself
+func read_allocs(samples []metrics.Sample) (int64, int64) {
+	metrics.Read(samples)
+	return int64(samples[0].Value.Uint64()), int64(samples[1].Value.Uint64())
+}
+
+// Counting OS threads
+
+// Threads the process runs now, -1 where /proc is missing
+func count_os_threads() int {
+	if task_dirs, err := os.ReadDir("/proc/self/task"); err == nil {
+		return len(task_dirs)
+	} else {
+		return -1
+	}
+}
+
+// Threads the runtime has created so far, exited ones included
+func count_created_threads() int {
+	return pprof.Lookup("threadcreate").Count()
+}
+
+// Sampling goroutines
+
+type GoroutineSample struct {
+	moment       time.Duration
+	n_goroutines int
+}
+
+// Samples every interval the goroutines started since the sampler,
+// the sampler itself left out, until the returned function stops it
+// and hands over the samples; an interval of 0 samples nothing
+func start_goroutine_sampler(interval time.Duration) func() []GoroutineSample {
+
+	if interval <= 0 {
+		return func() []GoroutineSample {
+			return nil
+		}
+	}
+
+	n_before := runtime.NumGoroutine() + 1
+	samples := []GoroutineSample{}
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+
+		defer close(stopped)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			samples = append(samples, GoroutineSample{now(), runtime.NumGoroutine() - n_before})
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return func() []GoroutineSample {
+		close(stop)
+		<-stopped
+		return samples
+	}
+}
+
+func observe(ctx context.Context, n_tasks int, setup Setup) Observation {
+
+	var obs Observation
+
+	if setup.is_streaming() {
+		obs = create_streamed_observation(
+			setup.get_workload().get_name(),
+			setup.get_executor_name(),
+			n_tasks)
+	} else {
+		obs = create_observation(
+			setup.get_workload().get_name(),
+			setup.get_executor_name(),
+			n_tasks)
+	}
+
+	obs.set_thread_locked(setup.is_thread_locked())
+	obs.set_series_size(setup.get_series_size())
+	obs.set_n_cycles(setup.get_n_cycles())
+	obs.set_experiment_idx(setup.get_experiment_idx())
+	obs.set_n_noise(setup.get_n_noise())
+	obs.set_outlier_rule(setup.get_outlier_rule())
+	obs.set_ci_method(setup.get_ci_method())
+	obs.set_max_cv(setup.get_max_cv())
+
+	if setup.get_histogram_bounds() != nil {
+		obs.set_histogram(create_histogram(setup.get_histogram_bounds()))
+	}
+
+	workload := setup.get_workload().prepare()
+
+	tasks_cycles := draw_tasks_cycles(n_tasks, setup.get_n_cycles(), setup.get_sizing())
+
+	// Observations and their tasks show up by name in a runtime trace
+	ctx, trace_task := trace.NewTask(ctx, fmt.Sprintf("observation of %d tasks", n_tasks))
+	defer trace_task.End()
+
+	group, group_ctx := create_task_group(ctx, setup.is_fail_fast())
+	defer group.release()
+
+	// Set just before the first launch; reporters get the times of a task
+	// counted from it, the report later counts them from the first launch
+	var started time.Duration
+
+	bound_ctx := group_ctx
+
+	if setup.is_bounded() {
+		var cancel context.CancelFunc
+		bound_ctx, cancel = context.WithTimeout(group_ctx, setup.get_bound())
+		defer cancel()
+		obs.set_bound(setup.get_bound())
+	}
+
+	run_task := func(task_idx int, launched time.Duration) error {
+
+		sched_latency := since(launched)
+
+		defer trace.StartRegion(group_ctx, "task").End()
+
+		if setup.is_thread_locked() {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+		}
+
+		task_ctx := group_ctx
+
+		if setup.get_task_timeout() > 0 {
+			var cancel context.CancelFunc
+			task_ctx, cancel = context.WithTimeout(group_ctx, setup.get_task_timeout())
+			defer cancel()
+		}
+
+		// Thread CPU time counts only while the task stays on its thread
+		cpu_timed := setup.is_cpu_timed() && setup.get_executor() != EX_Process
+
+		var user_before, system_before time.Duration
+
+		if cpu_timed {
+			runtime.LockOSThread()
+			defer runtime.UnlockOSThread()
+			user_before, system_before, cpu_timed = read_thread_cpu_time()
+		}
+
+		var alloc_samples []metrics.Sample
+		var bytes_before, allocs_before int64
+
+		if setup.are_allocs_counted() {
+			alloc_samples = create_alloc_samples()
+			bytes_before, allocs_before = read_allocs(alloc_samples)
+		}
+
+		var task Task
+
+		if setup.is_bounded() {
+			task = bounded_task(task_ctx, bound_ctx, workload, task_idx, tasks_cycles[task_idx], launched)
+		} else if setup.get_executor() == EX_Process {
+			task = process_task(task_ctx, workload, setup.get_child_options(), task_idx, tasks_cycles[task_idx], launched)
+		} else {
+			task = standard_task(task_ctx, workload, task_idx, tasks_cycles[task_idx], launched)
+		}
+
+		if cpu_timed {
+			if user_after, system_after, ok := read_thread_cpu_time(); ok {
+				task.set_cpu_time(user_after-user_before, system_after-system_before)
+			}
+		}
+
+		if setup.are_allocs_counted() {
+			bytes_after, allocs_after := read_allocs(alloc_samples)
+			task.set_allocs(bytes_after-bytes_before, allocs_after-allocs_before)
+		}
+
+		// Cancelled by its own timeout rather than by the measurement as a whole
+		if task.get_status() == TS_Cancelled && task_ctx.Err() == context.DeadlineExceeded && group_ctx.Err() == nil {
+			task.set_status(TS_TimedOut)
+		}
+
+		task.set_sched_latency(sched_latency)
+		obs.register_task(task)
+
+		reported := task
+		reported.recalc_start_relative(started)
+		setup.get_reporters().OnTaskDone(setup, reported)
+
+		if obs.has_histogram() && task.get_status() == TS_Done {
+			obs.get_histogram().record(task.get_duration())
+		}
+
+		return task.get_err()
+	}
+
+	launcher := create_launcher(
+		n_tasks,
+		setup.get_launch_order(),
+		setup.get_stagger(),
+		setup.get_launch_rate(),
+		setup.get_launch_burst())
+	obs.set_offered_rate(setup.get_launch_rate())
+
+	if setup.get_executor() == EX_OpenLoop {
+		obs.set_offered_rate(setup.get_arrival_rate())
+	}
+
+	stop_noise := start_noise(ctx, setup.get_n_noise())
+	defer stop_noise()
+
+	var mem_before runtime.MemStats
+	runtime.ReadMemStats(&mem_before)
+
+	energy_meter := open_energy_meter()
+	var energy_before []int64
+
+	// An observation whose counters fail to read leaves energy out
+	if energy_meter != nil {
+		if readings, err := energy_meter.read(); err == nil {
+			energy_before = readings
+		}
+	}
+
+	voluntary_before, involuntary_before, cs_read := read_context_switches()
+
+	threads_before := count_os_threads()
+	created_before := count_created_threads()
+
+	stop_sampler := start_goroutine_sampler(setup.get_sampling_interval())
+
+	var perf_counters *PerfCounters = nil
+
+	if setup.is_perf_counted() {
+		perf_counters = open_perf_counters()
+	}
+
+	started = now()
+
+	switch {
+	case setup.is_bounded():
+		// All tasks repeat side by side until the bound, so they form a single series
+		execute_batches(group_ctx, group, launcher, n_tasks, run_task)
+		obs.assign_series(launcher, n_tasks)
+	case setup.get_executor() == EX_Pool:
+		execute_pool(group_ctx, group, launcher, setup.get_series_size(), run_task)
+	case setup.get_executor() == EX_Semaphore:
+		execute_semaphore(group_ctx, group, launcher, setup.get_series_size(), run_task)
+	case setup.get_executor() == EX_Chunked:
+		execute_chunked(group_ctx, group, launcher, setup.get_series_size(), setup.get_chunk_size(), run_task)
+	case setup.get_executor() == EX_Graph:
+		graph := create_task_graph(n_tasks, setup.get_graph_shape(), setup.get_graph_width())
+		execute_graph(group_ctx, group, graph, run_task)
+		if !obs.is_streamed() {
+			obs.set_graph(graph.count_layers(), graph.calc_critical_path(obs.tasks))
+		}
+	case setup.get_executor() == EX_Process:
+		// Every goroutine of a series waits for its own child process
+		execute_batches(group_ctx, group, launcher, setup.get_series_size(), run_task)
+		obs.assign_series(launcher, setup.get_series_size())
+	case setup.get_executor() == EX_Overlap:
+		execute_overlapped(group_ctx, group, launcher, setup.get_series_size(), run_task)
+	case setup.get_executor() == EX_OpenLoop:
+		execute_open_loop(
+			group_ctx,
+			group,
+			n_tasks,
+			setup.get_series_size(),
+			setup.get_arrival_rate(),
+			run_task)
+	case setup.get_executor() == EX_RampUp:
+		obs.set_ramp(setup.get_series_size(), setup.get_ramp_period())
+		execute_ramp_up(
+			group_ctx,
+			group,
+			launcher,
+			setup.get_series_size(),
+			setup.get_ramp_period(),
+			run_task)
+	default:
+		execute_batches(group_ctx, group, launcher, setup.get_series_size(), run_task)
+		obs.assign_series(launcher, setup.get_series_size())
+	}
+
+	obs.set_first_failure(group.wait())
+	obs.set_goroutine_samples(stop_sampler())
+
+	if energy_before != nil {
+		if energy_after, err := energy_meter.read(); err == nil {
+			obs.set_energy_uj(energy_meter.calc_consumed_uj(energy_before, energy_after))
+		}
+	}
+
+	if perf_counters != nil {
+		obs.set_perf_counts(perf_counters.close_and_read())
+	}
+
+	obs.set_threads(threads_before, count_os_threads(), count_created_threads()-created_before)
+
+	if voluntary_after, involuntary_after, ok := read_context_switches(); cs_read && ok {
+		obs.set_context_switches(voluntary_after-voluntary_before, involuntary_after-involuntary_before)
+	}
+
+	var mem_after runtime.MemStats
+	runtime.ReadMemStats(&mem_after)
+	obs.set_gc(int(mem_after.NumGC-mem_before.NumGC), time.Duration(mem_after.PauseTotalNs-mem_before.PauseTotalNs))
+	obs.set_memory(
+		int64(mem_after.HeapInuse)-int64(mem_before.HeapInuse),
+		int64(mem_after.TotalAlloc-mem_before.TotalAlloc),
+		int64(mem_after.Mallocs-mem_before.Mallocs))
+
+	return obs
+}
+
+// Measuring energy
+
+const RAPL_DIR = "/sys/class/powercap"
+
+// Package energy counters of Intel and AMD CPUs exposed by the Linux
+// powercap framework; the counters wrap around at their max ranges
+type EnergyMeter struct {
+	counter_paths []string
+	max_ranges    []int64
+}
+
+func read_int64_file(path string) (int64, error) {
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+}
+
+// Microjoules consumed by each package so far; a counter that fails
+// to read spoils the readings, which would look like a wraparound
+func (m EnergyMeter) read() ([]int64, error) {
+
+	readings := make([]int64, len(m.counter_paths))
+
+	for idx, path := range m.counter_paths {
+
+		reading, err := read_int64_file(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		readings[idx] = reading
+	}
+
+	return readings, nil
+}
+
+func (m EnergyMeter) calc_consumed_uj(before, after []int64) int64 {
+
+	var consumed int64 = 0
+
+	for idx := range m.counter_paths {
+		if after[idx] >= before[idx] {
+			consumed += after[idx] - before[idx]
+		} else {
+			consumed += m.max_ranges[idx] - before[idx] + after[idx]
+		}
+	}
+
+	return consumed
+}
+
+// Returns nil when there are no package counters or they may not be read,
+// as on other systems or without root rights on recent kernels
+func open_energy_meter() *EnergyMeter {
+
+	domain_dirs, _ := filepath.Glob(filepath.Join(RAPL_DIR, "intel-rapl:*"))
+	meter := EnergyMeter{[]string{}, []int64{}}
+
+	for _, domain_dir := range domain_dirs {
+
+		name, err := os.ReadFile(filepath.Join(domain_dir, "name"))
+
+		if err != nil || !strings.HasPrefix(string(name), "package") {
+			continue
+		}
+
+		counter_path := filepath.Join(domain_dir, "energy_uj")
+		max_range, range_err := read_int64_file(filepath.Join(domain_dir, "max_energy_range_uj"))
+		_, counter_err := read_int64_file(counter_path)
+
+		if range_err == nil && counter_err == nil {
+			meter.counter_paths = append(meter.counter_paths, counter_path)
+			meter.max_ranges = append(meter.max_ranges, max_range)
+		}
+	}
+
+	if len(meter.counter_paths) > 0 {
+		return &meter
+	} else {
+		return nil
+	}
+}
+
+// Counting hardware events
+
+// Counts of events in all threads of the process,
+// -1 for an event that could not be counted
+type PerfCounts struct {
+	instructions     int64
+	cycles           int64
+	cache_misses     int64
+	context_switches int64
+}
+
+// Instructions per cycle
+func (c PerfCounts) get_ipc() float64 {
+	if c.instructions >= 0 && c.cycles > 0 {
+		return float64(c.instructions) / float64(c.cycles)
+	} else {
+		return 0
+	}
+}
+
+func (c PerfCounts) has_ipc() bool {
+	return c.instructions >= 0 && c.cycles > 0
+}
+
+func create_perf_counts() PerfCounts {
+	return PerfCounts{-1, -1, -1, -1}
+}
+
+// Capturing execution traces and profiles
+
+// Writes a runtime trace for go tool trace, of the whole measurement
+// or of the observations of n_tasks tasks of the first experiment only;
+// a nil tracer traces nothing
+type Tracer struct {
+	out_file *os.File
+	n_tasks  int
+	done     bool
+	running  bool
+}
+
+// A trace that fails to start, as when the program runs one already,
+// stops the measurement and is not stopped in turn
+func (t *Tracer) begin_observations(n_tasks int) error {
+
+	if t == nil || t.n_tasks != n_tasks || t.done {
+		return nil
+	}
+
+	if err := trace.Start(t.out_file); err != nil {
+		t.done = true
+		return fmt.Errorf("cannot start the trace: %w", err)
+	}
+
+	t.running = true
+
+	return nil
+}
+
+func (t *Tracer) end_observations(n_tasks int) {
+	if t != nil && t.n_tasks == n_tasks && t.running {
+		t.stop()
+	}
+}
+
+func (t *Tracer) stop() {
+	trace.Stop()
+	t.running = false
+	t.done = true
+}
+
+// Stops a trace still running, as when an interrupt comes between
+// the beginning and the end of the traced observations
+func (t *Tracer) close() {
+
+	if t == nil {
+		return
+	}
+
+	if t.running {
+		t.stop()
+	}
+
+	t.out_file.Close()
+}
+
+// Profiles the measurement for go tool pprof: CPU samples while it runs,
+// and the heap with the allocations made until it stops
+type Profiler struct {
+	cpu_file *os.File
+	mem_path string
+}
+
+// Either path may be empty, and a profiler with both empty is nil
+func start_profiler(cpu_path, mem_path string) (*Profiler, error) {
+
+	if cpu_path == "" && mem_path == "" {
+		return nil, nil
+	}
+
+	profiler := &Profiler{nil, mem_path}
+
+	if cpu_path != "" {
+
+		cpu_file, err := os.Create(cpu_path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if err := pprof.StartCPUProfile(cpu_file); err != nil {
+			cpu_file.Close()
+			return nil, err
+		}
+
+		profiler.cpu_file = cpu_file
+	}
+
+	return profiler, nil
+}
+
+func (p *Profiler) stop() error {
+
+	if p == nil {
+		return nil
+	}
+
+	if p.cpu_file != nil {
+		pprof.StopCPUProfile()
+		p.cpu_file.Close()
+	}
+
+	if p.mem_path != "" {
+
+		mem_file, err := os.Create(p.mem_path)
+
+		if err != nil {
+			return err
+		}
+
+		defer mem_file.Close()
+
+		// Brings the heap statistics of the profile up to date
+		runtime.GC()
+
+		return pprof.WriteHeapProfile(mem_file)
+	}
+
+	return nil
+}
+
+// Starts tracing the whole measurement at once when n_tasks is 0;
+// returns nil without a file path
+func open_tracer(out_file_path string, n_tasks int) (*Tracer, error) {
+
+	if out_file_path == "" {
+		return nil, nil
+	}
+
+	out_file, err := os.Create(out_file_path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	tracer := &Tracer{out_file, n_tasks, false, false}
+
+	if n_tasks == 0 {
+		if err := trace.Start(out_file); err != nil {
+			out_file.Close()
+			return nil, err
+		}
+		tracer.running = true
+	}
+
+	return tracer, nil
+}
+
+// Showing progress of the measurement
+
+const PROGRESS_BAR_WIDTH = 30
+
+// A bar on stderr with the share of task work done and the time remaining,
+// extrapolated from the observations completed so far; an observation
+// of n tasks counts as n units of work. A nil progress shows nothing
+type Progress struct {
+	total_work int
+	done_work  int
+	start      time.Duration
+	drawn      bool
+}
+
+// Returns nil when the bar is suppressed or stderr is not a terminal,
+// so that redirected output stays clean
+func open_progress(total_work int, quiet bool) *Progress {
+
+	if quiet || total_work <= 0 || !is_terminal(os.Stderr) {
+		return nil
+	}
+
+	return &Progress{total_work, 0, now(), false}
+}
+
+func is_terminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Work restored from a checkpoint takes no time, so it is left out of the estimate
+func (p *Progress) skip(work int) {
+	if p != nil {
+		p.total_work -= work
+	}
+}
+
+func (p *Progress) advance(work int) {
+	if p != nil {
+		p.done_work += work
+		p.draw()
+	}
+}
+
+// Time remaining if the rest of the work goes as fast as the work done
+func (p *Progress) estimate_remaining() time.Duration {
+	if p.done_work == 0 {
+		return 0
+	} else {
+		return time.Duration(float64(since(p.start)) * float64(p.total_work-p.done_work) / float64(p.done_work))
+	}
+}
+
+func (p *Progress) draw() {
+
+	if p == nil || p.done_work == 0 {
+		return
+	}
+
+	share := math.Min(float64(p.done_work)/float64(p.total_work), 1)
+	n_filled := int(share * PROGRESS_BAR_WIDTH)
+
+	fmt.Fprintf(os.Stderr, "\r[%s%s] %3.0f%%  ETA %v\033[K",
+		strings.Repeat("#", n_filled),
+		strings.Repeat(" ", PROGRESS_BAR_WIDTH-n_filled),
+		share*100,
+		p.estimate_remaining().Round(time.Second))
+
+	p.drawn = true
+}
+
+// Erases the bar before the results are printed
+func (p *Progress) clear() {
+	if p != nil && p.drawn {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+		p.drawn = false
+	}
+}
+
+// Observations of every task count, each repeated, for every setup
+func count_planned_work(task_counts []int, setups []Setup) int {
+
+	work := 0
+
+	for _, setup := range setups {
+		for _, n_tasks := range task_counts {
+			work += setup.count_reps() * n_tasks
+		}
+	}
+
+	return work
+}
+
+// Getting parameters of the current system
+
+func count_cpus() int {
+	return runtime.NumCPU()
+}
+
+func format_switch(on bool) string {
+	if on {
+		return "on"
+	} else {
+		return "off"
+	}
+}
+
+func is_async_preempt_off() bool {
+	return strings.Contains(os.Getenv("GODEBUG"), "asyncpreemptoff=1")
+}
+
+func count_cycles_per_sec(ctx context.Context) int {
+	return measure_cycles_per_sec(ctx, time.Second)
+}
+
+// Runs ever longer loops until one of them takes at least min_duration;
+// a cancelled calibration gives 0 for the caller to notice ctx
+func measure_cycles_per_sec(ctx context.Context, min_duration time.Duration) int {
+
+	var duration time.Duration = 0
+	var n_cycles int = 1
+
+	for duration < min_duration && ctx.Err() == nil {
+		n_cycles *= 10
+		start := now()
+		iterate(ctx, random_triplet(), n_cycles)
+		duration = since(start)
+	}
+
+	if ctx.Err() != nil {
+		return 0
+	} else {
+		return int(float64(n_cycles) / duration.Seconds())
+	}
+}
+
+// Clock readings of the harness per task: the scheduling latency,
+// the start, and the duration
+const HARNESS_CLOCK_READINGS = 3
+
+// Harness overhead beyond this share of a task duration is worth a warning
+const MAX_OVERHEAD_SHARE = 0.01
+
+const N_CLOCK_SAMPLES = 100000
+const N_LAUNCH_SAMPLES = 10000
+
+func measure_clock_cost() time.Duration {
+
+	start := now()
+
+	for idx := 0; idx < N_CLOCK_SAMPLES; idx++ {
+		now()
+	}
+
+	return since(start) / N_CLOCK_SAMPLES
+}
+
+// From a go statement to the end of an empty goroutine, one at a time
+func measure_launch_cost() time.Duration {
+
+	start := now()
+
+	for idx := 0; idx < N_LAUNCH_SAMPLES; idx++ {
+		done := make(chan struct{})
+		go func() {
+			close(done)
+		}()
+		<-done
+	}
+
+	return since(start) / N_LAUNCH_SAMPLES
+}
+
+func calibrate_n_cycles(ctx context.Context, task_duration time.Duration) int {
+
+	n_cycles := int(float64(count_cycles_per_sec(ctx)) * task_duration.Seconds())
+
+	if n_cycles < 1 {
+		return 1
+	} else {
+		return n_cycles
+	}
+}
+
+// Profiling the machine
+
+// What a machine is and how fast it runs the harness, saved by sys so that
+// measurements can name the machine they ran on by the id of the profile
+type MachineProfile struct {
+	id             string
+	host           string
+	cpu_model      string
+	n_cpus         int
+	cpu_mhz        float64
+	memory_bytes   int64
+	os_name        string
+	go_version     string
+	clock_cost     time.Duration
+	launch_cost    time.Duration
+	cycles_per_sec int
+	created        time.Time
+}
+
+func (p MachineProfile) get_id() string {
+	return p.id
+}
+
+func (p MachineProfile) get_cpu_model() string {
+	return p.cpu_model
+}
+
+func (p MachineProfile) get_os_name() string {
+	return p.os_name
+}
+
+func (p MachineProfile) get_go_version() string {
+	return p.go_version
+}
+
+func (p MachineProfile) get_memory_bytes() int64 {
+	return p.memory_bytes
+}
+
+func (p MachineProfile) get_cpu_mhz() float64 {
+	return p.cpu_mhz
+}
+
+func (p MachineProfile) get_clock_cost() time.Duration {
+	return p.clock_cost
+}
+
+func (p MachineProfile) get_launch_cost() time.Duration {
+	return p.launch_cost
+}
+
+func (p MachineProfile) get_cycles_per_sec() int {
+	return p.cycles_per_sec
+}
+
+// The id hashes the hardware and the software, not the calibration,
+// which varies from one run to the next on the same machine
+func calc_machine_id(host, cpu_model string, n_cpus int, memory_bytes int64, os_name, go_version string) string {
+
+	hash := fnv.New64a()
+
+	fmt.Fprintf(hash, "%s\n%s\n%d\n%d\n%s\n%s", host, cpu_model, n_cpus, memory_bytes, os_name, go_version)
+
+	return fmt.Sprintf("%016x", hash.Sum64())
+}
+
+func describe_os() string {
+	if release := read_os_release(); release != "" {
+		return fmt.Sprintf("%s/%s %s", runtime.GOOS, runtime.GOARCH, release)
+	} else {
+		return fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH)
+	}
+}
+
+// The id of the machine this process runs on, without calibrating
+func identify_machine() string {
+	host, _ := os.Hostname()
+	cpu_model, _ := read_cpu_model()
+	return calc_machine_id(host, cpu_model, count_cpus(), read_memory_bytes(), describe_os(), runtime.Version())
+}
+
+func profile_machine(clock_cost, launch_cost time.Duration, cycles_per_sec int) MachineProfile {
+
+	host, _ := os.Hostname()
+	cpu_model, cpu_mhz := read_cpu_model()
+
+	return MachineProfile{
+		identify_machine(),
+		host,
+		cpu_model,
+		count_cpus(),
+		cpu_mhz,
+		read_memory_bytes(),
+		describe_os(),
+		runtime.Version(),
+		clock_cost,
+		launch_cost,
+		cycles_per_sec,
+		time.Now()}
+}
+
+func format_machine_profile(profile MachineProfile) string {
+	return "Parameter,Value\n" +
+		fmt.Sprintf("CPUs,%d\n", profile.n_cpus) +
+		fmt.Sprintf("Clock reading ns,%d\n", profile.clock_cost.Nanoseconds()) +
+		fmt.Sprintf("Goroutine launch ns,%d\n", profile.launch_cost.Nanoseconds()) +
+		format_machine_rows(profile)
+}
+
+// Rows of a profile beyond those every report has in its header
+func format_machine_rows(profile MachineProfile) string {
+	return fmt.Sprintf("Profile id,%s\n", profile.id) +
+		fmt.Sprintf("Host,%s\n", profile.host) +
+		fmt.Sprintf("CPU model,%s\n", strings.ReplaceAll(profile.cpu_model, ",", " ")) +
+		fmt.Sprintf("CPU MHz,%.0f\n", profile.cpu_mhz) +
+		fmt.Sprintf("Memory bytes,%d\n", profile.memory_bytes) +
+		fmt.Sprintf("OS,%s\n", profile.os_name) +
+		fmt.Sprintf("Go version,%s\n", profile.go_version) +
+		fmt.Sprintf("Cycles per second,%d\n", profile.cycles_per_sec) +
+		fmt.Sprintf("Created,%s\n", profile.created.Format(time.RFC3339))
+}
+
+// Reads back a profile saved by sys, of which measurements need the id
+func load_machine_profile(path string) (MachineProfile, error) {
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return MachineProfile{}, err
+	}
+
+	values := map[string]string{}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if name, value, ok := strings.Cut(line, ","); ok {
+			values[name] = value
+		}
+	}
+
+	if values["Profile id"] == "" {
+		return MachineProfile{}, fmt.Errorf("%s: not a machine profile, save one with sys --machine", path)
+	}
+
+	created, _ := time.Parse(time.RFC3339, values["Created"])
+
+	return MachineProfile{
+		values["Profile id"],
+		values["Host"],
+		values["CPU model"],
+		parse_int(values["CPUs"]),
+		parse_float(values["CPU MHz"]),
+		int64(parse_int(values["Memory bytes"])),
+		values["OS"],
+		values["Go version"],
+		time.Duration(parse_int(values["Clock reading ns"])),
+		time.Duration(parse_int(values["Goroutine launch ns"])),
+		parse_int(values["Cycles per second"]),
+		created}, nil
+}
+
+// Printing messages to a console
+
+// Where the messages go, stdout unless a program measuring with Measure
+// sends them elsewhere; the report of a child task always goes to stdout
+var CONSOLE io.Writer = os.Stdout
+
+// Colors of ANSI terminals, which stay off when the console is not
+// a terminal or NO_COLOR is set, as --no-color does
+const (
+	COLOR_Green = "\033[32m"
+	COLOR_Red   = "\033[31m"
+	COLOR_Dim   = "\033[2m"
+	COLOR_Reset = "\033[0m"
+)
+
+// Profits this close to the ideal speedup stand out as good
+const GOOD_EFFICIENCY = 0.9
+
+func is_colored() bool {
+	return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" && CONSOLE == io.Writer(os.Stdout) && is_terminal(os.Stdout)
+}
+
+func colorize(color, text string) string {
+	if is_colored() {
+		return color + text + COLOR_Reset
+	} else {
+		return text
+	}
+}
+
+// Green near the ideal speedup, red where concurrency is a loss
+func format_profit(profit, efficiency float64, width int) string {
+
+	text := fmt.Sprintf("%*.0f%%", width-1, profit*100.0)
+
+	if efficiency >= GOOD_EFFICIENCY {
+		return colorize(COLOR_Green, text)
+	} else if profit < 0 {
+		return colorize(COLOR_Red, text)
+	} else {
+		return text
+	}
+}
+
+func print_separator(line string) {
+	fmt.Fprintln(CONSOLE, colorize(COLOR_Dim, line))
+}
+
+func print_salutation() {
+	fmt.Fprintf(CONSOLE, "Testing concurrent code execution on Go\n\n")
+}
+
+func print_help() {
+	fmt.Fprintln(CONSOLE, "Commands and arguments")
+	fmt.Fprintln(CONSOLE, "Displaying system parameters, saving them as a machine profile with --machine:")
+	fmt.Fprintln(CONSOLE, "sys [--machine <File>]")
+	fmt.Fprintln(CONSOLE, "Measuring profits of concurrency:")
+	fmt.Fprintln(CONSOLE, "profit --tasks <N> [--cycles <N>] [--series <N>|auto] [--out <File>] [Options]")
+	fmt.Fprintln(CONSOLE, "Comparing a goroutine per task with a worker pool on the same tasks:")
+	fmt.Fprintln(CONSOLE, "executors --tasks <N> [--cycles <N>] [--series <N>|auto] [--out <File>] [Options]")
+	fmt.Fprintln(CONSOLE, "Testing whether task durations of two saved reports differ significantly:")
+	fmt.Fprintln(CONSOLE, "compare <Report A> <Report B> [--alpha <p>]")
+	fmt.Fprintln(CONSOLE, "with label:<Text> or tag:<key=value> for a report, the latest matching one of")
+	fmt.Fprintln(CONSOLE, "--out-dir or the current directory, also narrowed by --tag:")
+	fmt.Fprintln(CONSOLE, "compare label:before-fix label:after-fix [--out-dir <Dir>] [--tag <key=value,...>]")
+	fmt.Fprintln(CONSOLE, "Listing saved reports with their labels and tags, filtered by them:")
+	fmt.Fprintln(CONSOLE, "list [--out-dir <Dir>] [--label <Text>] [--tag <key=value,...>]")
+	fmt.Fprintln(CONSOLE, "Printing a completion script, e.g. for source <(conctest completion bash):")
+	fmt.Fprintln(CONSOLE, "completion bash|zsh|fish")
+	fmt.Fprintln(CONSOLE, "Displaying this help, also with --help or -h after any command:")
+	fmt.Fprintln(CONSOLE, "help")
+	fmt.Fprintln(CONSOLE, "The commands s, p, and c stand for sys, profit, and executors")
+	fmt.Fprintln(CONSOLE, "Exit codes: 0 success, 1 a task failed with --fail-fast, 2 invalid arguments,")
+	fmt.Fprintln(CONSOLE, "3 a file could not be read or written, 4 a regression against --baseline,")
+	fmt.Fprintln(CONSOLE, "5 interrupted by a signal or --deadline with a partial report")
+	fmt.Fprintln(CONSOLE, "Measurement:")
+	fmt.Fprintln(CONSOLE, "--preset quick|standard|thorough   Tasks, cycles calibrated to a task duration, and repetitions")
+	fmt.Fprintln(CONSOLE, "                                   for a first look, a regular run, or a careful one")
+	fmt.Fprintln(CONSOLE, "--tasks <N>                        Observations of 1 to N tasks")
+	fmt.Fprintln(CONSOLE, "--tasks <A>..<B>[:step=<S>][,...]  Observations of the numbers of tasks in ranges and lists")
+	fmt.Fprintln(CONSOLE, "--cycles <N>                       Cycles in a task, also as a float like 1e7;")
+	fmt.Fprintf(CONSOLE, "                                   calibrated to %d ms per task when omitted\n", AUTO_TASK_MS)
+	fmt.Fprintln(CONSOLE, "--series <N>|auto                  Tasks in a series, auto by default")
+	fmt.Fprintln(CONSOLE, "                                   Ranges and lists of cycles and series sizes run every combination")
+	fmt.Fprintln(CONSOLE, "--out <File>                       Save the report as CSV")
+	fmt.Fprintln(CONSOLE, "--sys                              Check the system parameters first, as sys does, and keep them")
+	fmt.Fprintln(CONSOLE, "                                   with the machine speed in the report")
+	fmt.Fprintln(CONSOLE, "--machine <File>                   Name the machine profile saved by sys in the report, warn when")
+	fmt.Fprintln(CONSOLE, "                                   it describes another machine")
+	fmt.Fprintln(CONSOLE, "--out-dir <Dir>                    Save the report in the directory as host_tasks_cycles_date.csv,")
+	fmt.Fprintln(CONSOLE, "                                   never overwriting an earlier one")
+	fmt.Fprintln(CONSOLE, "--config <File>|-                  Read options from lines like tasks = 32 or workload = \"fsync\",")
+	fmt.Fprintln(CONSOLE, "                                   from stdin with -; the command line overrides them")
+	fmt.Fprintln(CONSOLE, "Every option can also be set by an environment variable, CONCTEST_TASK_MS=50 for --task-ms 50;")
+	fmt.Fprintln(CONSOLE, "the command line takes precedence over the environment, and the environment over --config")
+	fmt.Fprintln(CONSOLE, "--checkpoint <File>                Append each completed observation to the file, <Output file>.checkpoint")
+	fmt.Fprintln(CONSOLE, "                                   by default; the file is removed when the run completes")
+	fmt.Fprintln(CONSOLE, "--resume                           Continue an interrupted run from its checkpoint with the same options")
+	fmt.Fprintln(CONSOLE, "--batch <File>                     Run the experiments of the file one after another into one report:")
+	fmt.Fprintln(CONSOLE, "                                   a line of options like --workload fsync --tasks 8 per experiment,")
+	fmt.Fprintln(CONSOLE, "                                   or --config documents separated by ---; the command line applies to all")
+	fmt.Fprintln(CONSOLE, "--dry-run                          Show the planned observations and the estimated time, run nothing")
+	fmt.Fprintln(CONSOLE, "--every <Duration>                 Repeat the run on an interval like 10m until stopped, appending")
+	fmt.Fprintln(CONSOLE, "                                   a report with its round and start time to the file of --out")
+	fmt.Fprintln(CONSOLE, "--explain                          Define every metric by its formula and show the baselines it used,")
+	fmt.Fprintln(CONSOLE, "                                   also appended to the report")
+	fmt.Fprintln(CONSOLE, "-q, --quiet                        No progress bar with the time remaining on the terminal")
+	fmt.Fprintln(CONSOLE, "--no-color                         No colors on the terminal, as with the NO_COLOR variable set")
+	fmt.Fprintln(CONSOLE, "Options:")
+	fmt.Fprintln(CONSOLE, "--series-per-cpu <N>               Tasks in an omitted or auto series per CPU")
+	fmt.Fprintln(CONSOLE, "--dist fixed|uniform|exponential   Distribution of cycles in a task")
+	fmt.Fprintln(CONSOLE, "--spread <0..1>                    Relative spread of the uniform distribution")
+	fmt.Fprintln(CONSOLE, "--task-ms <ms>                     Calibrate cycles in a task to the duration, cycles may be 0")
+	fmt.Fprintln(CONSOLE, "--fixed-start                      Start every task from the same triplet or seed")
+	fmt.Fprintln(CONSOLE, "--workload float|integer|both      Arithmetic of the loop, both runs them back-to-back")
+	fmt.Fprintln(CONSOLE, "--workload go-sleep|cgo-sleep|sleep Blocking for a microsecond per cycle in Go or in C")
+	fmt.Fprintln(CONSOLE, "--workload fsync                   Appending and syncing a small record per cycle")
+	fmt.Fprintln(CONSOLE, "--fsync-dir <Directory>            Directory for the files of the fsync workload")
+	fmt.Fprintln(CONSOLE, "--workload pipeline                Passing an item per cycle through stages connected by channels")
+	fmt.Fprintln(CONSOLE, "--stages <N>                       Number of pipeline stages")
+	fmt.Fprintln(CONSOLE, "--buffer <N>                       Buffer size of the channels between pipeline stages")
+	fmt.Fprintln(CONSOLE, "--stage-cycles <N>                 Cycles spent by a pipeline stage on an item")
+	fmt.Fprintln(CONSOLE, "--workload map-sync|map-rwmutex|map-sharded|map")
+	fmt.Fprintln(CONSOLE, "                                   Reading or writing a shared map per cycle, map runs all three")
+	fmt.Fprintln(CONSOLE, "--workload tight                   Loops without function calls, hard to preempt")
+	fmt.Fprintln(CONSOLE, "--tight-len <N>                    Steps of a tight loop, one loop per cycle")
+	fmt.Fprintln(CONSOLE, "--preempt on|off                   Asynchronous preemption, off restarts with GODEBUG=asyncpreemptoff=1")
+	fmt.Fprintln(CONSOLE, "--keys <N>                         Number of keys in the shared map")
+	fmt.Fprintln(CONSOLE, "--write-ratio <0..1>               Share of writes among map operations")
+	fmt.Fprintln(CONSOLE, "--deadline <sec>                   Cancel the measurement after the time, as Ctrl-C does;")
+	fmt.Fprintln(CONSOLE, "                                   either way the report of the observations completed is marked partial")
+	fmt.Fprintln(CONSOLE, "--executor batch|pool|semaphore|all")
+	fmt.Fprintln(CONSOLE, "                                   Series of goroutines with a barrier, a pool of workers,")
+	fmt.Fprintln(CONSOLE, "                                   or all goroutines at once limited by a semaphore")
+	fmt.Fprintln(CONSOLE, "--executor process|scaling         Series of child processes running a task each,")
+	fmt.Fprintln(CONSOLE, "                                   scaling runs them and batch for comparison")
+	fmt.Fprintln(CONSOLE, "--executor chunked                 Sending chunks of tasks to a pool of workers over a channel")
+	fmt.Fprintln(CONSOLE, "--chunk <N>[,<N>...]               Chunk sizes of the chunked executor, each one measured")
+	fmt.Fprintln(CONSOLE, "--executor graph                   Goroutines waiting for the tasks they depend on")
+	fmt.Fprintln(CONSOLE, "--graph fan|layers                 Layers alternating between one task and width tasks,")
+	fmt.Fprintln(CONSOLE, "                                   or all width tasks wide, each depending on the previous one")
+	fmt.Fprintln(CONSOLE, "--width <N>                        Width of the graph layers, the series size by default")
+	fmt.Fprintln(CONSOLE, "--executor overlap|series          Series starting a task as soon as a slot frees up,")
+	fmt.Fprintln(CONSOLE, "                                   series runs it and batch for comparison")
+	fmt.Fprintln(CONSOLE, "--executor open                    Tasks arriving at random and queueing up for workers")
+	fmt.Fprintln(CONSOLE, "--arrival-rate <Tasks per second>  Mean arrival rate of the open executor")
+	fmt.Fprintln(CONSOLE, "--executor ramp                    Adding a worker every ramp period up to the series size")
+	fmt.Fprintln(CONSOLE, "--ramp-ms <ms>                     Ramp period of the ramp executor")
+	fmt.Fprintln(CONSOLE, "--bound-ms <ms>                    Let all tasks repeat for the time and count completed cycles,")
+	fmt.Fprintln(CONSOLE, "                                   as a single batch of the batch executor")
+	fmt.Fprintln(CONSOLE, "--gomaxprocs <N>                   GOMAXPROCS for the run, added to the output file name")
+	fmt.Fprintln(CONSOLE, "--noise <N>                        Keep N goroutines busy during every observation")
+	fmt.Fprintln(CONSOLE, "--task-timeout <ms>                Cancel a task running longer, leave it out of task statistics;")
+	fmt.Fprintln(CONSOLE, "                                   a cgo-sleep task runs in C and ignores it")
+	fmt.Fprintln(CONSOLE, "--outliers none|iqr|mad            Flag outlying task durations, show statistics without them")
+	fmt.Fprintln(CONSOLE, "--max-cv <x>                       Flag observations with a larger coefficient of variation")
+	fmt.Fprintln(CONSOLE, "--cpu-time                         Lock tasks to OS threads and read the CPU time of the threads")
+	fmt.Fprintln(CONSOLE, "--sample-ms <ms>                   Count goroutines of the tasks every interval during observations")
+	fmt.Fprintln(CONSOLE, "--seed <N>                         Seed the random draws of the run, which the report records")
+	fmt.Fprintln(CONSOLE, "--label <Text>                     Annotate the report with a label like before-fix")
+	fmt.Fprintln(CONSOLE, "--tag <key=value,...>              Annotate the report with tags like branch=main")
+	fmt.Fprintln(CONSOLE, "--task-allocs                      Count bytes and objects allocated while each task runs,")
+	fmt.Fprintln(CONSOLE, "                                   mixed with other tasks running at the same time")
+	fmt.Fprintln(CONSOLE, "--perf                             Count instructions, cycles, cache misses, and context switches")
+	fmt.Fprintln(CONSOLE, "                                   with perf_event_open on Linux")
+	fmt.Fprintln(CONSOLE, "--trace <File>                     Write a runtime trace of the measurement for go tool trace")
+	fmt.Fprintln(CONSOLE, "--trace-tasks <N>                  Trace only the observations of N tasks of the first experiment")
+	fmt.Fprintln(CONSOLE, "--cpuprofile <File>                Write a pprof CPU profile of the measurement")
+	fmt.Fprintln(CONSOLE, "--memprofile <File>                Write a pprof heap profile at the end of the measurement")
+	fmt.Fprintln(CONSOLE, "--histogram none|log|<ms>[,<ms>...]")
+	fmt.Fprintln(CONSOLE, "                                   Count task durations in log-spaced buckets or up to the bounds")
+	fmt.Fprintln(CONSOLE, "--baseline <File>                  Exit with 4 if the run regresses against a saved report")
+	fmt.Fprintln(CONSOLE, "                                   or has no observation in common with it")
+	fmt.Fprintln(CONSOLE, "--tolerance <Percent>              Allowed growth of mean task duration, drop of profit in points")
+	fmt.Fprintln(CONSOLE, "--fail-fast                        Abort the measurement on the first failed task")
+	fmt.Fprintln(CONSOLE, "--warmup <N>                       Run N throwaway observations of a full series first")
+	fmt.Fprintln(CONSOLE, "--cold-warm                        Observe one task twice first, cold then warm, show the difference")
+	fmt.Fprintln(CONSOLE, "--normalize                        Calibrate the machine speed first, show durations in its cycles")
+	fmt.Fprintln(CONSOLE, "--streaming                        Keep running statistics instead of tasks, for huge task counts;")
+	fmt.Fprintln(CONSOLE, "                                   leaves out schedules and tables of single tasks")
+	fmt.Fprintln(CONSOLE, "--reps <N>                         Repeat each observation N times")
+	fmt.Fprintln(CONSOLE, "--aggregate mean|median            Statistic shown for repeated observations")
+	fmt.Fprintln(CONSOLE, "--ci t|bootstrap                   Confidence intervals by Student's t over repetitions,")
+	fmt.Fprintln(CONSOLE, "                                   or by resampling task durations, also without repetitions")
+	fmt.Fprintln(CONSOLE, "--lock-thread off|on|both          Lock each task to an OS thread, both runs with and without")
+	fmt.Fprintln(CONSOLE, "--order forward|reverse|shuffle    Order of launching tasks")
+	fmt.Fprintln(CONSOLE, "--stagger <ms>                     Random pause of up to the time between launches")
+	fmt.Fprintln(CONSOLE, "--rate <Tasks per second>          Limit launches with a token bucket, except of graph, ramp, and open")
+	fmt.Fprintln(CONSOLE, "--burst <N>                        Launches the token bucket lets through at once")
+}
+
+func print_usage_error(err error) {
+	fmt.Fprintln(os.Stderr, "Error:", err)
+	fmt.Fprintln(os.Stderr, "Run with help for commands and options")
+}
+
+func print_sysparams_header() {
+	print_separator("====================================")
+	fmt.Fprintln(CONSOLE, "System parameter               Value")
+	print_separator("====================================")
+}
+
+func print_cpus(n_cpus int) {
+	fmt.Fprintf(CONSOLE, "CPUs available %21d\n", n_cpus)
+}
+
+func print_gomaxprocs(gomaxprocs int) {
+	fmt.Fprintf(CONSOLE, "GOMAXPROCS: %d, async preemption: %s\n\n", gomaxprocs, format_switch(!is_async_preempt_off()))
+}
+
+func print_seed(seed int64) {
+	fmt.Fprintf(CONSOLE, "Seed: %d, repeat the random draws with --seed %d\n\n", seed, seed)
+}
+
+func print_clock_cost(clock_cost time.Duration) {
+	fmt.Fprintf(CONSOLE, "Clock reading, ns %18d\n", clock_cost.Nanoseconds())
+}
+
+func print_launch_cost(launch_cost time.Duration) {
+	fmt.Fprintf(CONSOLE, "Goroutine launch, ns %15d\n", launch_cost.Nanoseconds())
+}
+
+func print_overheads(report *Report) {
+	fmt.Fprintf(CONSOLE, "Harness overhead: %d ns per clock reading, %d ns per goroutine launch\n\n",
+		report.get_clock_cost().Nanoseconds(),
+		report.get_launch_cost().Nanoseconds())
+}
+
+func print_overhead_warning(report *Report, n_dominated int) {
+	fmt.Fprintf(CONSOLE, "\nWarning: in %d observations the harness overhead of %.2f µs per task exceeds %.0f%% of the mean task duration\n",
+		n_dominated,
+		float64(report.get_harness_overhead().Nanoseconds())/1000,
+		MAX_OVERHEAD_SHARE*100)
+}
+
+func print_cycles_per_sec(cycles_per_sec int) {
+	fmt.Fprintf(CONSOLE, "Cycles per second %18v\n", cycles_per_sec)
+}
+
+func print_machine_speed(cycles_per_sec int) {
+	fmt.Fprintf(CONSOLE, "Calibrated machine speed: %d cycles per second\n\n", cycles_per_sec)
+}
+
+func print_normalized_header() {
+	fmt.Fprintln(CONSOLE, "\nDurations in calibrated cycles, millions")
+	fmt.Fprintln(CONSOLE, "Tasks  Mean task duration  Total duration")
+}
+
+func print_normalized_entry(report *Report, obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %19.2f %15.2f\n",
+		obs.count_tasks(),
+		report.to_cycles(obs.get_mean_task_duration())/1e6,
+		report.to_cycles(obs.get_total_duration())/1e6)
+}
+
+func print_normalized(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_normalized_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_normalized_entry(report, report.get_observation(idx))
+	}
+}
+
+func print_dry_run_header(n_task_counts, tasks_max, cycles_per_sec int) {
+	fmt.Fprintf(CONSOLE, "Dry run: %d task counts up to %d tasks, %d cycles per second calibrated briefly\n\n",
+		n_task_counts, tasks_max, cycles_per_sec)
+	print_separator("=================================================================================")
+	fmt.Fprintln(CONSOLE, "Workload      Executor     Series       Cycles  Reps  Observations  Estimated time")
+	print_separator("=================================================================================")
+}
+
+func print_dry_run_entry(entry PlanEntry) {
+	setup := entry.get_setup()
+	fmt.Fprintf(CONSOLE, "%-13s %-12s %6d %12d %5d %13d %15v\n",
+		setup.get_workload().get_name(),
+		setup.get_executor_name(),
+		setup.get_series_size(),
+		setup.get_n_cycles(),
+		setup.count_reps(),
+		entry.count_observations(),
+		entry.get_duration().Round(time.Millisecond))
+}
+
+func print_dry_run_footer(entries []PlanEntry) {
+
+	n_observations := 0
+	duration := time.Duration(0)
+
+	for _, entry := range entries {
+		n_observations += entry.count_observations()
+		duration += entry.get_duration()
+	}
+
+	print_separator("=================================================================================")
+	fmt.Fprintf(CONSOLE, "Total %58d %15v\n", n_observations, duration.Round(time.Millisecond))
+	fmt.Fprintln(CONSOLE, "\nThe estimate leaves out calibrations and overheads of executors")
+}
+
+func print_resumed(n_restored int, path string) {
+	fmt.Fprintf(CONSOLE, "Resuming with %d observations restored from %s\n\n", n_restored, path)
+}
+
+func print_checkpoint_kept(path string) {
+	fmt.Fprintf(CONSOLE, "\nCompleted observations are kept in %s, continue with --resume\n", path)
+}
+
+func print_calibrated_cycles(task_ms int, n_cycles int) {
+	fmt.Fprintf(CONSOLE, "Calibrated cycles in a task: %d (%d ms)\n\n", n_cycles, task_ms)
+}
+
+func print_auto_series_size(series_size, n_cpus int) {
+	fmt.Fprintf(CONSOLE, "Tasks in a series: %d (%d CPUs)\n\n", series_size, n_cpus)
+}
+
+func print_energy_counters(available bool) {
+	if available {
+		fmt.Fprintf(CONSOLE, "Energy counters %20s\n", "RAPL")
+	} else {
+		fmt.Fprintf(CONSOLE, "Energy counters %20s\n", "none")
+	}
+}
+
+func print_sysparams_footer() {
+	print_separator("====================================")
+}
+
+func print_machine(profile MachineProfile) {
+	fmt.Fprintf(CONSOLE, "CPU model: %s\n", profile.get_cpu_model())
+	fmt.Fprintf(CONSOLE, "CPU frequency: %.0f MHz, memory: %d MB\n", profile.get_cpu_mhz(), profile.get_memory_bytes()>>20)
+	fmt.Fprintf(CONSOLE, "OS: %s, Go: %s\n", profile.get_os_name(), profile.get_go_version())
+}
+
+func print_machine_profile_saved(id, path string) {
+	fmt.Fprintf(CONSOLE, "Machine profile %s saved to %s\n", id, path)
+}
+
+func print_machine_mismatch(profile_id, machine_id string) {
+	fmt.Fprintf(CONSOLE, "Warning: the machine profile %s describes another machine than this one, %s\n\n", profile_id, machine_id)
+}
+
+func print_workload_title(setup Setup) {
+
+	fmt.Fprintf(CONSOLE, "Workload: %s, executor: %s",
+		setup.get_workload().get_name(),
+		setup.get_executor_name())
+
+	if setup.is_thread_locked() {
+		fmt.Fprint(CONSOLE, ", tasks locked to OS threads")
+	}
+
+	if setup.get_executor() == EX_Graph {
+		fmt.Fprintf(CONSOLE, ", %s graph %d wide", format_graph_shape(setup.get_graph_shape()), setup.get_graph_width())
+	}
+
+	if setup.get_n_noise() > 0 {
+		fmt.Fprintf(CONSOLE, ", %d noise goroutines", setup.get_n_noise())
+	}
+
+	if setup.is_swept() {
+		fmt.Fprintf(CONSOLE, ", series of %d, %d cycles", setup.get_series_size(), setup.get_n_cycles())
+	}
+
+	if setup.get_experiment_idx() > 0 {
+		fmt.Fprintf(CONSOLE, ", experiment %d", setup.get_experiment_idx())
+	}
+
+	fmt.Fprintln(CONSOLE)
+}
+
+func print_warmup(n_warmups int) {
+	fmt.Fprintf(CONSOLE, "Warming up with %d throwaway observations\n", n_warmups)
+}
+
+func print_cold_start(cold_start *ColdStart) {
+	fmt.Fprintf(CONSOLE, "Cold start: %.2f ms, warm: %.2f ms, difference: %.2f ms (%.1f%%)\n",
+		to_ms(cold_start.get_cold_duration()),
+		to_ms(cold_start.get_warm_duration()),
+		to_ms(cold_start.get_difference()),
+		cold_start.get_relative_difference())
+}
+
+func print_profit_header() {
+	print_separator("====================================================================================================")
+	fmt.Fprintln(CONSOLE, "Tasks  Mean task duration  Std. dev.  Total duration  Cost  Profit  Speedup  Efficiency  Utilization")
+	print_separator("====================================================================================================")
+}
+
+func print_profit_entry(obs *Observation) {
+
+	fmt.Fprintf(CONSOLE, "%5d %19.2f %10.2f %15.2f %4.0f%% %s %8.2f %10.0f%% %11.0f%%",
+		obs.count_tasks(),
+		to_ms(obs.get_mean_task_duration()),
+		obs.get_standard_deviation(),
+		to_ms(obs.get_total_duration()),
+		obs.get_concurrency_cost()*100.0,
+		format_profit(obs.get_concurrency_profit(), obs.get_efficiency(), 7),
+		obs.get_speedup(),
+		obs.get_efficiency()*100.0,
+		obs.get_utilization()*100.0)
+
+	if obs.is_interrupted() {
+		fmt.Fprintf(CONSOLE, "  %d failed, %d timed out, %d cancelled, %d not started",
+			obs.count_tasks_with_status(TS_Failed),
+			obs.count_tasks_with_status(TS_TimedOut),
+			obs.count_tasks_with_status(TS_Cancelled),
+			obs.count_tasks_with_status(TS_Pending))
+	}
+
+	if obs.is_unreliable() {
+		fmt.Fprintf(CONSOLE, "  unreliable, CV %.2f", obs.get_cv())
+	}
+
+	fmt.Fprintln(CONSOLE)
+
+	if obs.get_first_failure() != nil {
+		fmt.Fprintf(CONSOLE, "      First failure: %v\n", obs.get_first_failure())
+	}
+}
+
+func print_bounded_header(bound time.Duration) {
+	fmt.Fprintf(CONSOLE, "Observations bounded by %d ms\n", bound.Milliseconds())
+	print_separator("====================================================================================================")
+	fmt.Fprintln(CONSOLE, "Tasks  Completed runs  Cycles per second  Speedup")
+	print_separator("====================================================================================================")
+}
+
+func print_bounded_entry(reps Repetitions, aggregate Aggregate) {
+
+	fmt.Fprintf(CONSOLE, "%5d %15.0f %18.0f %8.2f",
+		reps.get_first().count_tasks(),
+		reps.get_aggregate(metric_completed_runs, aggregate),
+		reps.get_aggregate(metric_cycles_per_sec, aggregate),
+		reps.get_aggregate(metric_throughput_speedup, aggregate))
+
+	if reps.count_reps() > 1 {
+		fmt.Fprintf(CONSOLE, "  %s of %d reps", format_aggregate(aggregate), reps.count_reps())
+	}
+
+	fmt.Fprintln(CONSOLE)
+}
+
+func print_repetitions_entry(reps Repetitions, aggregate Aggregate) {
+	fmt.Fprintf(CONSOLE, "%5d %19.0f %10.1f %15.0f %4.0f%% %s %8.2f %10.0f%% %11.0f%%  %s of %d reps\n",
+		reps.get_first().count_tasks(),
+		reps.get_aggregate(metric_mean_task_duration, aggregate),
+		reps.get_aggregate(metric_standard_deviation, aggregate),
+		reps.get_aggregate(metric_total_duration, aggregate),
+		reps.get_aggregate(metric_concurrency_cost, aggregate)*100.0,
+		format_profit(reps.get_aggregate(metric_concurrency_profit, aggregate), reps.get_aggregate(metric_efficiency, aggregate), 7),
+		reps.get_aggregate(metric_speedup, aggregate),
+		reps.get_aggregate(metric_efficiency, aggregate)*100.0,
+		reps.get_aggregate(metric_utilization, aggregate)*100.0,
+		format_aggregate(aggregate),
+		reps.count_reps())
+}
+
+func print_jitter_header() {
+	fmt.Fprintln(CONSOLE, "\nRun-to-run jitter over repetitions")
+	fmt.Fprintln(CONSOLE, "Tasks  Total duration range  Relative spread  Profit range")
+}
+
+func print_jitter_entry(reps Repetitions) {
+	fmt.Fprintf(CONSOLE, "%5d %21.0f %15.1f%% %12.1f%%\n",
+		reps.get_first().count_tasks(),
+		reps.get_range(metric_total_duration),
+		reps.get_relative_spread(metric_total_duration)*100.0,
+		reps.get_range(metric_concurrency_profit)*100.0)
+}
+
+// The noise floor is the largest relative spread of total duration:
+// smaller differences between experiments may be just noise
+func print_jitter(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_jitter_header()
+	}
+
+	noise_floor := 0.0
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		if obs := report.get_observation(idx); obs.get_rep_idx() == 0 {
+			reps := report.collect_repetitions(obs)
+			print_jitter_entry(reps)
+			noise_floor = math.Max(noise_floor, reps.get_relative_spread(metric_total_duration))
+		}
+	}
+
+	fmt.Fprintf(CONSOLE, "Noise floor: total duration varies by up to %.1f%% between runs\n", noise_floor*100.0)
+}
+
+func print_confidence_header() {
+	fmt.Fprintln(CONSOLE, "\n95% confidence intervals over repetitions")
+	fmt.Fprintln(CONSOLE, "Tasks  Mean task duration          Cost          Profit")
+}
+
+func print_confidence_entry(reps Repetitions) {
+	fmt.Fprintf(CONSOLE, "%5d %10.0f ± %6.1f %6.0f%% ± %4.1f%% %8.0f%% ± %4.1f%%\n",
+		reps.get_first().count_tasks(),
+		reps.get_mean(metric_mean_task_duration),
+		reps.get_confidence(metric_mean_task_duration),
+		reps.get_mean(metric_concurrency_cost)*100.0,
+		reps.get_confidence(metric_concurrency_cost)*100.0,
+		reps.get_mean(metric_concurrency_profit)*100.0,
+		reps.get_confidence(metric_concurrency_profit)*100.0)
+}
+
+func print_bootstrap_header() {
+	fmt.Fprintln(CONSOLE, "\n95% bootstrap intervals")
+	fmt.Fprintln(CONSOLE, "Tasks  Mean task duration  p95 task duration          Profit")
+}
+
+func print_bootstrap_entry(reps Repetitions, seed int64) {
+
+	mean_low, mean_high := reps.get_bootstrap_task_interval(mean_of, seed)
+	p95_low, p95_high := reps.get_bootstrap_task_interval(p95_of, seed)
+
+	fmt.Fprintf(CONSOLE, "%5d %19s %18s %15s\n",
+		reps.get_first().count_tasks(),
+		fmt.Sprintf("%.1f..%.1f", mean_low, mean_high),
+		fmt.Sprintf("%.1f..%.1f", p95_low, p95_high),
+		format_profit_interval(reps, seed))
+}
+
+// The profit is one value per repetition, so a single repetition has no interval
+func format_profit_interval(reps Repetitions, seed int64) string {
+	if reps.count_reps() < 2 {
+		return "n/a"
+	} else {
+		profit_low, profit_high := reps.get_bootstrap_interval(metric_concurrency_profit, seed)
+		return fmt.Sprintf("%.0f%%..%.0f%%", profit_low*100.0, profit_high*100.0)
+	}
+}
+
+func print_bootstrap(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_bootstrap_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		if obs := report.get_observation(idx); obs.get_rep_idx() == 0 {
+			print_bootstrap_entry(report.collect_repetitions(obs), report.get_seed())
+		}
+	}
+}
+
+func print_confidence(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_confidence_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		if obs := report.get_observation(idx); obs.get_rep_idx() == 0 {
+			print_confidence_entry(report.collect_repetitions(obs))
+		}
+	}
+}
+
+func print_scalability(points []ScalePoint) {
+
+	serial_fraction := fit_amdahl(points)
+	contention, coherence := fit_usl(points)
+
+	fmt.Fprintln(CONSOLE, "\nScalability models fitted to the speedups")
+	fmt.Fprintf(CONSOLE, "Amdahl's law: serial fraction %.4f", serial_fraction)
+
+	if serial_fraction > 0 {
+		fmt.Fprintf(CONSOLE, ", speedup limit %.2f", 1/serial_fraction)
+	}
+
+	fmt.Fprintln(CONSOLE)
+	fmt.Fprintf(CONSOLE, "Universal Scalability Law: contention %.4f, coherence %.6f", contention, coherence)
+
+	if optimum := find_usl_optimum(contention, coherence); !math.IsInf(optimum, 1) {
+		fmt.Fprintf(CONSOLE, ", optimal concurrency %.1f", optimum)
+	}
+
+	fmt.Fprintln(CONSOLE)
+}
+
+func print_regressions(baseline_path string, regressions []string, n_matched int) {
+
+	if n_matched == 0 {
+		fmt.Fprintln(CONSOLE, colorize(COLOR_Red, fmt.Sprintf("Nothing to compare with %s: no observation of the same workload, executor, and tasks", baseline_path)))
+		return
+	}
+
+	if len(regressions) == 0 {
+		fmt.Fprintf(CONSOLE, "No regressions against %s\n", baseline_path)
+		return
+	}
+
+	fmt.Fprintln(CONSOLE, colorize(COLOR_Red, fmt.Sprintf("Regressions against %s:", baseline_path)))
+
+	for _, regression := range regressions {
+		fmt.Fprintf(CONSOLE, "  %s\n", colorize(COLOR_Red, regression))
+	}
+}
+
+func print_comparisons(path_a, path_b string, comparisons []SampleComparison, alpha float64) {
+
+	fmt.Fprintf(CONSOLE, "Welch's t-test on task durations, A: %s, B: %s\n", path_a, path_b)
+	fmt.Fprintln(CONSOLE, "Workload      Executor      Tasks  Mean A  Mean B  Difference, %        t  p-value  Significant")
+
+	for _, comparison := range comparisons {
+		significant := "no"
+		if comparison.is_significant(alpha) {
+			significant = "yes"
+		}
+		fmt.Fprintf(CONSOLE, "%-13s %-13s %5d %7.2f %7.2f %14.1f %8.2f %8.4f  %s\n",
+			comparison.key.workload_name,
+			comparison.key.executor_name,
+			comparison.key.n_tasks,
+			comparison.mean_a,
+			comparison.mean_b,
+			comparison.get_relative_difference(),
+			comparison.t,
+			comparison.p,
+			significant)
+	}
+}
+
+func print_saved_reports(dir string, reports []SavedReport) {
+
+	if len(reports) == 0 {
+		fmt.Fprintf(CONSOLE, "No saved reports in %s match\n", dir)
+		return
+	}
+
+	label_width := len("Label")
+	tags_width := len("Tags")
+
+	for _, report := range reports {
+		label_width = max(label_width, len(report.get_labels().get_label()))
+		tags_width = max(tags_width, len(strings.Join(report.get_labels().get_tags(), " ")))
+	}
+
+	fmt.Fprintf(CONSOLE, "%-19s  %-*s  %-*s  %s\n", "Modified", label_width, "Label", tags_width, "Tags", "Report")
+
+	for _, report := range reports {
+		fmt.Fprintf(CONSOLE, "%-19s  %-*s  %-*s  %s\n",
+			report.get_modified().Format(time.DateTime),
+			label_width, report.get_labels().get_label(),
+			tags_width, strings.Join(report.get_labels().get_tags(), " "),
+			report.get_path())
+	}
+}
+
+func print_unsaved_text(path, text string) {
+	fmt.Fprintf(CONSOLE, "\n%s could not be saved, so here is what was to go there:\n\n%s", path, text)
+}
+
+func print_unsaved_report(path string, report *Report, explained bool) {
+	fmt.Fprintf(CONSOLE, "\n%s could not be saved, so here is what was to go there:\n\n", path)
+	write_report(CONSOLE, report, explained)
+}
+
+func print_perf_unavailable() {
+	fmt.Fprintln(CONSOLE, "\nHardware events could not be counted, see perf_event_paranoid")
+}
+
+func print_abort(err error) {
+	fmt.Fprintf(CONSOLE, "Aborted on the first failure: %v\n", err)
+}
+
+func print_explanation(report *Report) {
+
+	fmt.Fprintln(CONSOLE, "\nHow the metrics are computed")
+
+	for _, quantity := range QUANTITIES {
+		fmt.Fprintf(CONSOLE, "%s  %s\n", get_quantity_symbol(quantity), describe_quantity(quantity))
+	}
+
+	for _, formula := range FORMULAS {
+		fmt.Fprintf(CONSOLE, "%-18s = %s\n", formula.get_metric_name(), formula.get_text())
+	}
+
+	for _, baseline := range report.collect_baselines() {
+		largest := report.find_largest_observation(baseline)
+		fmt.Fprintf(CONSOLE, "\n%s on %s, series of %d, %d cycles: B = %g ms per cycle from %d tasks over %d reps\n",
+			baseline.get_workload_name(),
+			baseline.get_executor_name(),
+			baseline.get_series_size(),
+			baseline.get_n_cycles(),
+			baseline.get_baseline_ms_per_cycle(),
+			baseline.count_tasks(),
+			report.collect_repetitions(baseline).count_reps())
+		fmt.Fprintf(CONSOLE, "With %d tasks, rep %d:\n", largest.count_tasks(), largest.get_rep_idx()+1)
+		for _, formula := range FORMULAS {
+			fmt.Fprintf(CONSOLE, "%-18s = %s = %.4f\n", formula.get_metric_name(), formula.substitute(largest), formula.evaluate(largest))
+		}
+	}
+}
+
+// The name of a file in an output directory is not known beforehand
+func print_out_file_path(path string) {
+	fmt.Fprintf(CONSOLE, "\nReport saved to %s\n", path)
+}
+
+func print_round_title(round int, started time.Time) {
+	fmt.Fprintf(CONSOLE, "\nRound %d at %s\n\n", round, started.Format(time.DateTime))
+}
+
+func print_next_round(next time.Time) {
+	fmt.Fprintf(CONSOLE, "\nNext round at %s, Ctrl-C to stop\n", next.Format(time.DateTime))
+}
+
+func print_experiment_title(experiment_idx, n_experiments int) {
+	fmt.Fprintf(CONSOLE, "\nExperiment %d of %d\n", experiment_idx, n_experiments)
+}
+
+func print_partial(report *Report) {
+	fmt.Fprintf(CONSOLE, "\nPartial report, %s: %d observations recorded\n",
+		report.get_interruption(),
+		report.count_observations())
+}
+
+func print_stage_times_header(n_stages int) {
+	fmt.Fprintln(CONSOLE, "\nMean busy time of pipeline stages per task")
+	fmt.Fprint(CONSOLE, "Tasks")
+	for stage_idx := 1; stage_idx <= n_stages; stage_idx++ {
+		fmt.Fprintf(CONSOLE, " %8s", fmt.Sprintf("Stage %d", stage_idx))
+	}
+	fmt.Fprintln(CONSOLE)
+}
+
+func print_stage_times_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d", obs.count_tasks())
+	for _, stage_time := range obs.get_mean_stage_times() {
+		fmt.Fprintf(CONSOLE, " %8.2f", to_ms(stage_time))
+	}
+	fmt.Fprintln(CONSOLE)
+}
+
+func print_stage_times(report *Report, first_idx int) {
+
+	header_printed := false
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		obs := report.get_observation(idx)
+		if obs.count_stages() > 0 {
+			if !header_printed {
+				print_stage_times_header(obs.count_stages())
+				header_printed = true
+			}
+			print_stage_times_entry(obs)
+		}
+	}
+}
+
+func print_queue_waits_header() {
+	fmt.Fprintln(CONSOLE, "\nQueueing delay versus service time")
+	fmt.Fprintln(CONSOLE, "Tasks  Mean queue wait  Max queue wait  Mean service time  Wait share, %")
+}
+
+func print_queue_waits_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %16.2f %15.2f %18.2f %14.1f\n",
+		obs.count_tasks(),
+		to_ms(obs.get_mean_queue_wait()),
+		to_ms(obs.get_max_queue_wait()),
+		to_ms(obs.get_mean_task_duration()),
+		obs.get_wait_share()*100)
+}
+
+func print_rates_header() {
+	fmt.Fprintln(CONSOLE, "\nOffered versus achieved rate")
+	fmt.Fprintln(CONSOLE, "Tasks  Offered, tasks per second  Achieved, tasks per second")
+}
+
+func print_rates_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %26.2f %27.2f\n",
+		obs.count_tasks(),
+		obs.get_offered_rate(),
+		obs.get_achieved_rate())
+}
+
+func print_littles_law_header() {
+	fmt.Fprintln(CONSOLE, "\nLittle's law, L = λW")
+	fmt.Fprintln(CONSOLE, "Tasks  Arrival rate, per sec  Mean latency      L     λW  Deviation, %  Load  Saturated")
+}
+
+func print_littles_law_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %22.2f %13.2f %6.2f %6.2f %13.1f %5.2f  %t\n",
+		obs.count_tasks(),
+		obs.get_achieved_rate(),
+		to_ms(obs.get_mean_latency()),
+		obs.get_mean_in_system(),
+		obs.get_rate_latency_product(),
+		obs.get_littles_law_deviation(),
+		obs.get_offered_load(),
+		obs.is_saturated())
+}
+
+func print_littles_law(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_littles_law_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_littles_law_entry(report.get_observation(idx))
+	}
+}
+
+func print_rates(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_rates_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_rates_entry(report.get_observation(idx))
+	}
+}
+
+func print_graph_paths_header() {
+	fmt.Fprintln(CONSOLE, "\nDependencies versus achievable profit")
+	fmt.Fprintln(CONSOLE, "Tasks  Layers  Total work  Critical path  Parallelism  Total duration")
+}
+
+func print_graph_paths_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %7d %11.2f %14.2f %12.2f %15.2f\n",
+		obs.count_tasks(),
+		obs.count_graph_layers(),
+		to_ms(obs.get_total_work()),
+		to_ms(obs.get_critical_path()),
+		obs.get_parallelism(),
+		to_ms(obs.get_total_duration()))
+}
+
+func print_graph_paths(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_graph_paths_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_graph_paths_entry(report.get_observation(idx))
+	}
+}
+
+func print_makespan_header() {
+	fmt.Fprintln(CONSOLE, "\nMakespan breakdown, % of slot time")
+	fmt.Fprintln(CONSOLE, "Tasks  Slots  Total duration   Work  Gaps  Tail")
+}
+
+func print_makespan_entry(obs *Observation) {
+	work, gaps, tail := obs.get_makespan_shares()
+	fmt.Fprintf(CONSOLE, "%5d %6d %15.2f %6.1f %5.1f %5.1f\n",
+		obs.count_tasks(),
+		obs.count_slots(),
+		to_ms(obs.get_total_duration()),
+		work,
+		gaps,
+		tail)
+}
+
+func print_makespan(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_makespan_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_makespan_entry(report.get_observation(idx))
+	}
+}
+
+func print_energy_header() {
+	fmt.Fprintln(CONSOLE, "\nPackage energy")
+	fmt.Fprintln(CONSOLE, "Tasks  Joules  Joules per task")
+}
+
+func print_energy_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %7.2f %16.3f\n",
+		obs.count_tasks(),
+		obs.get_energy_joules(),
+		obs.get_joules_per_task())
+}
+
+func print_energy(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_energy_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_energy_entry(report.get_observation(idx))
+	}
+}
+
+func print_threads_header() {
+	fmt.Fprintln(CONSOLE, "\nOS threads of the process")
+	fmt.Fprintln(CONSOLE, "Tasks  Before  After  Created")
+}
+
+func print_threads_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %7s %6s %8d\n",
+		obs.count_tasks(),
+		format_thread_count(obs.count_threads_before(), "-"),
+		format_thread_count(obs.count_threads_after(), "-"),
+		obs.count_threads_created())
+}
+
+func print_threads(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_threads_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_threads_entry(report.get_observation(idx))
+	}
+}
+
+func print_goroutine_samples_header() {
+	fmt.Fprintln(CONSOLE, "\nGoroutines sampled during observations")
+	fmt.Fprintln(CONSOLE, "Tasks  Samples  Mean goroutines  Max goroutines")
+}
+
+func print_goroutine_samples_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %8d %16.1f %15d\n",
+		obs.count_tasks(),
+		len(obs.get_goroutine_samples()),
+		obs.get_mean_sampled_goroutines(),
+		obs.get_max_sampled_goroutines())
+}
+
+func print_goroutine_samples(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_goroutine_samples_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_goroutine_samples_entry(report.get_observation(idx))
+	}
+}
+
+func print_context_switches_header() {
+	fmt.Fprintln(CONSOLE, "\nContext switches during observations")
+	fmt.Fprintln(CONSOLE, "Tasks  Voluntary  Involuntary  Per task")
+}
+
+func print_context_switches_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %10d %12d %9.1f\n",
+		obs.count_tasks(),
+		obs.count_voluntary_cs(),
+		obs.count_involuntary_cs(),
+		obs.get_context_switches_per_task())
+}
+
+func print_context_switches(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_context_switches_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_context_switches_entry(report.get_observation(idx))
+	}
+}
+
+func print_perf_counts_header() {
+	fmt.Fprintln(CONSOLE, "\nHardware events during observations")
+	fmt.Fprintln(CONSOLE, "Tasks    Instructions          Cycles   IPC    Cache misses  Context switches")
+}
+
+func print_perf_counts_entry(obs *Observation) {
+
+	counts := obs.get_perf_counts()
+
+	fmt.Fprintf(CONSOLE, "%5d %15s %15s %5s %15s %17s\n",
+		obs.count_tasks(),
+		format_perf_count(counts.instructions, "-"),
+		format_perf_count(counts.cycles, "-"),
+		format_ipc(counts, "-"),
+		format_perf_count(counts.cache_misses, "-"),
+		format_perf_count(counts.context_switches, "-"))
+}
+
+func print_perf_counts(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_perf_counts_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_perf_counts_entry(report.get_observation(idx))
+	}
+}
+
+func print_gc_header() {
+	fmt.Fprintln(CONSOLE, "\nGarbage collection during observations")
+	fmt.Fprintln(CONSOLE, "Tasks  Collections  Total pause  Pause share, %")
+}
+
+func print_gc_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %12d %12.3f %15.2f\n",
+		obs.count_tasks(),
+		obs.count_gc(),
+		to_ms(obs.get_gc_pause()),
+		obs.get_gc_pause_share()*100)
+}
+
+func print_gc(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_gc_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_gc_entry(report.get_observation(idx))
+	}
+}
+
+func print_cpu_times_header() {
+	fmt.Fprintln(CONSOLE, "\nCPU time versus wall time per task")
+	fmt.Fprintln(CONSOLE, "Tasks  Mean wall time  Mean CPU time    User  System  CPU share, %")
+}
+
+func print_cpu_times_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %15.2f %14.2f %7.2f %7.2f %13.1f\n",
+		obs.count_tasks(),
+		to_ms(obs.get_mean_task_duration()),
+		to_ms(obs.get_mean_cpu_time()),
+		to_ms(obs.get_mean_user_time()),
+		to_ms(obs.get_mean_system_time()),
+		obs.get_cpu_share()*100)
+}
+
+func print_cpu_times(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_cpu_times_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_cpu_times_entry(report.get_observation(idx))
+	}
+}
+
+func print_throughput_header() {
+	fmt.Fprintln(CONSOLE, "\nThroughput")
+	fmt.Fprintln(CONSOLE, "Tasks  Tasks per second  Cycles per second")
+}
+
+func print_throughput_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %17.2f %18.0f\n",
+		obs.count_tasks(),
+		obs.get_tasks_per_sec(),
+		obs.get_cycles_per_sec())
+}
+
+func print_histogram(obs *Observation) {
+
+	histogram := obs.get_histogram()
+
+	fmt.Fprintf(CONSOLE, "\nHistogram of task durations, %d tasks\n", obs.count_tasks())
+	fmt.Fprintln(CONSOLE, "  From      To   Count")
+
+	for bucket_idx := 0; bucket_idx < histogram.count_buckets(); bucket_idx++ {
+		if count := histogram.get_bucket_count(bucket_idx); count > 0 {
+			fmt.Fprintf(CONSOLE, "%6d %7s %7d\n",
+				histogram.get_bucket_from(bucket_idx).Milliseconds(),
+				histogram.format_bucket_to(bucket_idx),
+				count)
+		}
+	}
+}
+
+func print_throughput(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_throughput_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_throughput_entry(report.get_observation(idx))
+	}
+}
+
+func print_percentiles_header() {
+	fmt.Fprintln(CONSOLE, "\nTask durations from the fastest to the slowest task")
+	fmt.Fprintln(CONSOLE, "Tasks      Min      p50      p90      p95      p99      Max  Slowest task  Skewness  Kurtosis")
+}
+
+func print_percentiles_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %8.2f %8.2f %8.2f %8.2f %8.2f %8.2f %13d %9.2f %9.2f\n",
+		obs.count_tasks(),
+		to_ms(obs.get_min_task_duration()),
+		obs.get_duration_percentile(50),
+		obs.get_duration_percentile(90),
+		obs.get_duration_percentile(95),
+		obs.get_duration_percentile(99),
+		to_ms(obs.get_max_task_duration()),
+		obs.find_slowest_task()+1,
+		obs.get_skewness(),
+		obs.get_kurtosis())
+}
+
+func print_percentiles(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_percentiles_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_percentiles_entry(report.get_observation(idx))
+	}
+}
+
+func print_trimmed_header(rule OutlierRule) {
+	fmt.Fprintf(CONSOLE, "\nTask durations without outliers by %s\n", format_outlier_rule(rule))
+	fmt.Fprintln(CONSOLE, "Tasks  Outliers  Mean task duration  Std. dev.")
+}
+
+func print_trimmed_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %9d %19.2f %10.2f\n",
+		obs.count_tasks(),
+		obs.count_outliers(),
+		obs.get_trimmed_mean(),
+		obs.get_trimmed_standard_deviation())
+}
+
+func print_trimmed(report *Report, first_idx int, rule OutlierRule) {
+
+	if first_idx < report.count_observations() {
+		print_trimmed_header(rule)
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_trimmed_entry(report.get_observation(idx))
+	}
+}
+
+func print_latencies_header() {
+	fmt.Fprintln(CONSOLE, "\nLatency from launching a task to its finish")
+	fmt.Fprintln(CONSOLE, "Tasks  Mean latency  Max latency  Total duration")
+}
+
+func print_latencies_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %13.2f %12.2f %15.2f\n",
+		obs.count_tasks(),
+		to_ms(obs.get_mean_latency()),
+		to_ms(obs.get_max_latency()),
+		to_ms(obs.get_total_duration()))
+}
+
+func print_latencies(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_latencies_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_latencies_entry(report.get_observation(idx))
+	}
+}
+
+func print_series_header() {
+	fmt.Fprintln(CONSOLE, "\nSeries and idle slots at their tails")
+	fmt.Fprintln(CONSOLE, "Tasks  Series  Mean series duration  Idle at tails  Idle share, %")
+}
+
+func print_series_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %7d %21.2f %14.2f %14.1f\n",
+		obs.count_tasks(),
+		obs.count_series(),
+		to_ms(obs.get_mean_series_duration()),
+		to_ms(obs.sum_series_idle_tails()),
+		obs.get_idle_tail_share())
+}
+
+func print_series(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_series_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_series_entry(report.get_observation(idx))
+	}
+}
+
+func print_sched_latencies_header() {
+	fmt.Fprintln(CONSOLE, "\nScheduling latency from a go statement to the task, µs")
+	fmt.Fprintln(CONSOLE, "Tasks     Mean      Max")
+}
+
+func print_sched_latencies_entry(obs *Observation) {
+	fmt.Fprintf(CONSOLE, "%5d %8d %8d\n",
+		obs.count_tasks(),
+		obs.get_mean_sched_latency().Microseconds(),
+		obs.get_max_sched_latency().Microseconds())
+}
+
+func print_sched_latencies(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_sched_latencies_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_sched_latencies_entry(report.get_observation(idx))
+	}
+}
+
+func print_queue_waits(report *Report, first_idx int) {
+
+	if first_idx < report.count_observations() {
+		print_queue_waits_header()
+	}
+
+	for idx := first_idx; idx < report.count_observations(); idx++ {
+		print_queue_waits_entry(report.get_observation(idx))
+	}
+}
+
+func print_ramp_throughput(obs *Observation) {
+
+	fmt.Fprintf(CONSOLE, "\nThroughput while ramping up to %d workers over %d tasks\n",
+		obs.ramp_workers,
+		obs.count_tasks())
+	fmt.Fprintln(CONSOLE, "Workers     Since  Tasks per second")
+
+	for n_workers := 1; n_workers <= obs.ramp_workers; n_workers++ {
+		window_start, _ := obs.get_ramp_window(n_workers)
+		fmt.Fprintf(CONSOLE, "%7d %9d %17.1f\n",
+			n_workers,
+			window_start.Milliseconds(),
+			obs.get_ramp_throughput(n_workers))
+	}
+}
+
+func print_convergency(initial_triplet Triplet, step int, member float64) {
+	fmt.Fprintf(CONSOLE, "The sequence has converged: %f, %f, and %f give %f since step %d.\n",
+		initial_triplet[0],
+		initial_triplet[1],
+		initial_triplet[2],
+		member,
+		step)
+}
+
+func print_profit_separator() {
+	print_separator("----------------------------------------------------------------------------------------------------")
+}
+
+func print_profit_footer() {
+	print_separator("====================================================================================================")
+}
+
+func print_comparison_header() {
+	fmt.Fprintln(CONSOLE, "Goroutine per task versus worker pool")
+	print_separator("==================================================================")
+	fmt.Fprintln(CONSOLE, "Tasks  Goroutines, ms  Pool, ms  Difference, ms  Per task, µs")
+	print_separator("==================================================================")
+}
+
+func print_comparison_entry(n_tasks int, goroutines_ms, pool_ms float64) {
+	fmt.Fprintf(CONSOLE, "%5d %15.2f %9.2f %15.2f %13.1f\n",
+		n_tasks,
+		goroutines_ms,
+		pool_ms,
+		goroutines_ms-pool_ms,
+		(goroutines_ms-pool_ms)*1000/float64(n_tasks))
+}
+
+func print_comparison_footer() {
+	fmt.Fprintf(CONSOLE, "==================================================================\n\n")
+}
+
+func print_profit_duration(duration time.Duration) {
+	fmt.Fprintf(CONSOLE, "\nTotal duration: %d sec.\n\n", int(duration.Seconds()))
+}
+
+// Formatting and saving a report
+
+func format_observation_totals_section_header() string {
+	return "Tasks,Mean task duration,Std. dev.,Total duration,Cost,Profit,Workload,Cancelled,Executor,Failed,Mean queue wait,Rep,Locked threads,Series size,Noise goroutines,Offered rate,Achieved rate,Mean sched latency us,Mean latency,Max latency,Timed out,Variance,p50,p90,p95,p99,Outliers,Trimmed mean,Trimmed std. dev.,Speedup,Efficiency,CV,Unreliable,Min task duration,Max task duration,Slowest task,Utilization,Tasks per second,Cycles per second,Work share,Gap share,Tail share,Skewness,Kurtosis,Energy J,Energy per task J,Mean CPU time,Mean user time,Mean system time,CPU share,GC count,GC pause,Heap growth,Allocated bytes,Mallocs,Allocated bytes per task,Instructions,Cycles,IPC,Cache misses,Context switches,Voluntary context switches,Involuntary context switches,Threads before,Threads after,Threads created,Max queue wait,Wait share,Cycles in a task,Experiment\n"
+}
+
+func format_observation_totals(obs *Observation) string {
+
+	work_share, gap_share, tail_share := obs.get_makespan_shares()
+
+	return fmt.Sprintf("%d, %f, %f, %f, %f%%, %f%%, %s, %d, %s, %d, %f, %d, %t, %d, %d, %f, %f, %d, %f, %f, %d, %f, %f, %f, %f, %f, %d, %f, %f, %f, %f%%, %f, %t, %f, %f, %d, %f%%, %f, %f, %f%%, %f%%, %f%%, %f, %f, %s, %s, %s, %s, %s, %s, %d, %f, %d, %d, %d, %f, %s, %s, %s, %s, %s, %s, %s, %s, %s, %d, %f, %f%%, %d, %d\n",
+		obs.count_tasks(),
+		to_ms(obs.get_mean_task_duration()),
+		obs.get_standard_deviation(),
+		to_ms(obs.get_total_duration()),
+		obs.get_concurrency_cost()*100.0,
+		obs.get_concurrency_profit()*100.0,
+		obs.get_workload_name(),
+		obs.count_tasks_with_status(TS_Cancelled)+obs.count_tasks_with_status(TS_Pending),
+		obs.get_executor_name(),
+		obs.count_tasks_with_status(TS_Failed),
+		to_ms(obs.get_mean_queue_wait()),
+		obs.get_rep_idx()+1,
+		obs.is_thread_locked(),
+		obs.get_series_size(),
+		obs.get_n_noise(),
+		obs.get_offered_rate(),
+		obs.get_achieved_rate(),
+		obs.get_mean_sched_latency().Microseconds(),
+		to_ms(obs.get_mean_latency()),
+		to_ms(obs.get_max_latency()),
+		obs.count_tasks_with_status(TS_TimedOut),
+		obs.get_variance(),
+		obs.get_duration_percentile(50),
+		obs.get_duration_percentile(90),
+		obs.get_duration_percentile(95),
+		obs.get_duration_percentile(99),
+		obs.count_outliers(),
+		obs.get_trimmed_mean(),
+		obs.get_trimmed_standard_deviation(),
+		obs.get_speedup(),
+		obs.get_efficiency()*100.0,
+		obs.get_cv(),
+		obs.is_unreliable(),
+		to_ms(obs.get_min_task_duration()),
+		to_ms(obs.get_max_task_duration()),
+		obs.find_slowest_task()+1,
+		obs.get_utilization()*100.0,
+		obs.get_tasks_per_sec(),
+		obs.get_cycles_per_sec(),
+		work_share,
+		gap_share,
+		tail_share,
+		obs.get_skewness(),
+		obs.get_kurtosis(),
+		format_energy(obs, obs.get_energy_joules()),
+		format_energy(obs, obs.get_joules_per_task()),
+		format_cpu_time(obs, obs.get_mean_cpu_time()),
+		format_cpu_time(obs, obs.get_mean_user_time()),
+		format_cpu_time(obs, obs.get_mean_system_time()),
+		format_cpu_share(obs),
+		obs.count_gc(),
+		to_ms(obs.get_gc_pause()),
+		obs.get_heap_growth(),
+		obs.get_alloc_bytes(),
+		obs.count_mallocs(),
+		obs.get_alloc_bytes_per_task(),
+		format_perf_count(obs.get_perf_counts().instructions, ""),
+		format_perf_count(obs.get_perf_counts().cycles, ""),
+		format_ipc(obs.get_perf_counts(), ""),
+		format_perf_count(obs.get_perf_counts().cache_misses, ""),
+		format_perf_count(obs.get_perf_counts().context_switches, ""),
+		format_perf_count(obs.count_voluntary_cs(), ""),
+		format_perf_count(obs.count_involuntary_cs(), ""),
+		format_thread_count(obs.count_threads_before(), ""),
+		format_thread_count(obs.count_threads_after(), ""),
+		obs.count_threads_created(),
+		to_ms(obs.get_max_queue_wait()),
+		obs.get_wait_share()*100.0,
+		obs.get_n_cycles(),
+		obs.get_experiment_idx())
+}
+
+// Empty where energy could not be measured
+func format_energy(obs *Observation, joules float64) string {
+	if obs.is_energy_measured() {
+		return fmt.Sprintf("%f", joules)
+	} else {
+		return ""
+	}
+}
+
+// The placeholder where the event could not be counted
+func format_perf_count(count int64, placeholder string) string {
+	if count >= 0 {
+		return strconv.FormatInt(count, 10)
+	} else {
+		return placeholder
+	}
+}
+
+func format_ipc(counts PerfCounts, placeholder string) string {
+	if counts.has_ipc() {
+		return fmt.Sprintf("%.2f", counts.get_ipc())
+	} else {
+		return placeholder
+	}
+}
+
+func format_thread_count(n_threads int, placeholder string) string {
+	if n_threads >= 0 {
+		return strconv.Itoa(n_threads)
+	} else {
+		return placeholder
+	}
+}
+
+// Empty where CPU time was not measured
+func format_cpu_time(obs *Observation, cpu_time time.Duration) string {
+	if obs.is_cpu_timed() {
+		return fmt.Sprintf("%f", to_ms(cpu_time))
+	} else {
+		return ""
+	}
+}
+
+func format_cpu_share(obs *Observation) string {
+	if obs.is_cpu_timed() {
+		return fmt.Sprintf("%f%%", obs.get_cpu_share()*100.0)
+	} else {
+		return ""
+	}
+}
+
+// Sections of the report are written a row at a time, so that a report
+// of many tasks never sits in memory as a whole
+
+// A section goes after a blank line, headed by the names of its columns,
+// and only when it has a row
+type SectionWriter struct {
+	w       io.Writer
+	header  string
+	started bool
+}
+
+func create_section_writer(w io.Writer, header string) *SectionWriter {
+	return &SectionWriter{w, header, false}
+}
+
+func (s *SectionWriter) write_row(row string) {
+
+	if !s.started {
+		io.WriteString(s.w, "\n"+s.header)
+		s.started = true
+	}
+
+	io.WriteString(s.w, row)
+}
+
+func write_observation_totals_section(w io.Writer, report *Report) {
+
+	io.WriteString(w, format_observation_totals_section_header())
+
+	for _, obs := range report.observations {
+		io.WriteString(w, format_observation_totals(&obs))
+	}
+}
+
+func format_task(n_tasks, task_idx int, task *Task, obs *Observation, outlier bool) string {
+	return fmt.Sprintf("%d,%d,%f,%f,%f,%d,%s,%s,%s,%f,%d,%f,%d,%d,%t,%s,%s,%s,%s\n",
+		n_tasks,
+		task_idx,
+		to_ms(task.get_start()),
+		to_ms(task.get_finish()),
+		to_ms(task.get_duration()),
+		task.get_n_cycles(),
+		obs.get_workload_name(),
+		format_task_status(task.get_status()),
+		obs.get_executor_name(),
+		to_ms(task.get_queue_wait()),
+		obs.get_rep_idx()+1,
+		to_ms(task.get_launched()),
+		task.get_n_runs(),
+		task.get_sched_latency().Microseconds(),
+		outlier,
+		format_task_cpu_time(task, task.get_user_time()),
+		format_task_cpu_time(task, task.get_system_time()),
+		format_task_allocs(task, task.get_alloc_bytes()),
+		format_task_allocs(task, task.count_allocs()))
+}
+
+func format_task_allocs(task *Task, count int64) string {
+	if task.are_allocs_counted() {
+		return strconv.FormatInt(count, 10)
+	} else {
+		return ""
+	}
+}
+
+func format_task_cpu_time(task *Task, cpu_time time.Duration) string {
+	if task.is_cpu_timed() {
+		return fmt.Sprintf("%f", to_ms(cpu_time))
+	} else {
+		return ""
+	}
+}
+
+func write_tasks(w io.Writer, obs *Observation) {
+
+	n_tasks := obs.count_tasks()
+	low, high := obs.get_outlier_bounds()
+	task_idx := 1
+
+	for _, task := range obs.tasks {
+		io.WriteString(w, format_task(n_tasks, task_idx, &task, obs, obs.is_outlier(&task, low, high)))
+		task_idx++
+	}
+}
+
+func format_observation_schedule_header() string {
+	return "Tasks,Task,Started,Finished,Duration,Cycles,Workload,Status,Executor,Queue wait,Rep,Launched,Runs,Sched latency us,Outlier,User time,System time,Allocated bytes,Allocations\n"
+}
+
+func write_observation_schedules_section(w io.Writer, report *Report) {
+
+	io.WriteString(w, format_observation_schedule_header())
+
+	for _, obs := range report.observations {
+		write_tasks(w, &obs)
+	}
+}
+
+func format_stage_times_header() string {
+	return "Tasks,Task,Stage,Busy,Workload,Executor\n"
+}
+
+func write_stage_times(section *SectionWriter, obs *Observation) {
+	for task_idx, task := range obs.tasks {
+		for stage_idx, stage_time := range task.get_stage_times() {
+			section.write_row(fmt.Sprintf("%d,%d,%d,%f,%s,%s\n",
+				obs.count_tasks(),
+				task_idx+1,
+				stage_idx+1,
+				to_ms(stage_time),
+				obs.get_workload_name(),
+				obs.get_executor_name()))
+		}
+	}
+}
+
+func write_stage_times_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_stage_times_header())
+
+	for _, obs := range report.observations {
+		write_stage_times(section, &obs)
+	}
+}
+
+func format_repetitions_header() string {
+	return "Tasks,Workload,Executor,Reps," +
+		"Mean total duration,Median total duration," +
+		"Mean cost,Median cost,Mean profit,Median profit," +
+		"Mean task duration,Mean task duration CI,Total duration CI,Cost CI,Profit CI," +
+		"Total duration range,Total duration relative spread,Profit range\n"
+}
+
+func format_repetitions(reps Repetitions) string {
+	return fmt.Sprintf("%d,%s,%s,%d,%f,%f,%f%%,%f%%,%f%%,%f%%,%f,%f,%f,%f%%,%f%%,%f,%f%%,%f%%\n",
+		reps.get_first().count_tasks(),
+		reps.get_first().get_workload_name(),
+		reps.get_first().get_executor_name(),
+		reps.count_reps(),
+		reps.get_mean(metric_total_duration),
+		reps.get_median(metric_total_duration),
+		reps.get_mean(metric_concurrency_cost)*100.0,
+		reps.get_median(metric_concurrency_cost)*100.0,
+		reps.get_mean(metric_concurrency_profit)*100.0,
+		reps.get_median(metric_concurrency_profit)*100.0,
+		reps.get_mean(metric_mean_task_duration),
+		reps.get_confidence(metric_mean_task_duration),
+		reps.get_confidence(metric_total_duration),
+		reps.get_confidence(metric_concurrency_cost)*100.0,
+		reps.get_confidence(metric_concurrency_profit)*100.0,
+		reps.get_range(metric_total_duration),
+		reps.get_relative_spread(metric_total_duration)*100.0,
+		reps.get_range(metric_concurrency_profit)*100.0)
+}
+
+// Written when any number of tasks was observed more than once
+func write_repetitions_section(w io.Writer, report *Report) {
+
+	has_reps := false
+
+	for idx := range report.observations {
+		has_reps = has_reps || report.get_observation(idx).get_rep_idx() > 0
+	}
+
+	if !has_reps {
+		return
+	}
+
+	section := create_section_writer(w, format_repetitions_header())
+
+	for idx := range report.observations {
+		if obs := report.get_observation(idx); obs.get_rep_idx() == 0 {
+			section.write_row(format_repetitions(report.collect_repetitions(obs)))
+		}
+	}
+}
+
+func format_scalability_header() string {
+	return "Workload,Executor,Serial fraction,Contention,Coherence,Optimal concurrency\n"
+}
+
+func format_scalability(obs *Observation, points []ScalePoint) string {
+
+	serial_fraction := fit_amdahl(points)
+	contention, coherence := fit_usl(points)
+
+	return fmt.Sprintf("%s,%s,%f,%f,%f,%f\n",
+		obs.get_workload_name(),
+		obs.get_executor_name(),
+		serial_fraction,
+		contention,
+		coherence,
+		find_usl_optimum(contention, coherence))
+}
+
+// Fits the models to each experiment, which is a run of observations
+// starting from one task
+func write_scalability_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_scalability_header())
+
+	for idx := range report.observations {
+		obs := report.get_observation(idx)
+		if obs.count_tasks() == 1 && obs.get_rep_idx() == 0 {
+			points := []ScalePoint{}
+			for _, point := range report.collect_scale_points(idx) {
+				if len(points) > 0 && point.n_tasks == 1 {
+					break
+				}
+				points = append(points, point)
+			}
+			if len(points) > 2 {
+				section.write_row(format_scalability(obs, points))
+			}
+		}
+	}
+}
+
+func format_series_header() string {
+	return "Tasks,Series,Started,Finished,Duration,Idle tail,Workload,Executor,Rep\n"
+}
+
+func write_series(section *SectionWriter, obs *Observation) {
+
+	for series_idx := 0; series_idx < obs.count_series(); series_idx++ {
+		series_start, series_finish := obs.get_series_window(series_idx)
+		section.write_row(fmt.Sprintf("%d,%d,%f,%f,%f,%f,%s,%s,%d\n",
+			obs.count_tasks(),
+			series_idx+1,
+			to_ms(series_start),
+			to_ms(series_finish),
+			to_ms(series_finish-series_start),
+			to_ms(obs.get_series_idle_tail(series_idx)),
+			obs.get_workload_name(),
+			obs.get_executor_name(),
+			obs.get_rep_idx()+1))
+	}
+}
+
+func write_series_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_series_header())
+
+	for _, obs := range report.observations {
+		if obs.has_series() && !obs.is_bounded() {
+			write_series(section, &obs)
+		}
+	}
+}
+
+func format_littles_law_header() string {
+	return "Tasks,Arrival rate,Mean latency,Mean in system,Rate times latency,Deviation %,Load,Saturated,Workload,Executor,Rep\n"
+}
+
+func format_littles_law(obs *Observation) string {
+	return fmt.Sprintf("%d,%f,%f,%f,%f,%f,%f,%t,%s,%s,%d\n",
+		obs.count_tasks(),
+		obs.get_achieved_rate(),
+		to_ms(obs.get_mean_latency()),
+		obs.get_mean_in_system(),
+		obs.get_rate_latency_product(),
+		obs.get_littles_law_deviation(),
+		obs.get_offered_load(),
+		obs.is_saturated(),
+		obs.get_workload_name(),
+		obs.get_executor_name(),
+		obs.get_rep_idx()+1)
+}
+
+func write_littles_law_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_littles_law_header())
+
+	for _, obs := range report.observations {
+		if obs.is_rate_driven() {
+			section.write_row(format_littles_law(&obs))
+		}
+	}
+}
+
+func format_concurrency_levels_header() string {
+	return "Tasks,From,To,Active tasks,Workload,Executor,Rep\n"
+}
+
+// A row per stretch of time with the same number of active tasks
+func write_concurrency_levels(section *SectionWriter, obs *Observation) {
+
+	for _, level := range obs.collect_concurrency_levels() {
+		section.write_row(fmt.Sprintf("%d,%f,%f,%d,%s,%s,%d\n",
+			obs.count_tasks(),
+			to_ms(level.from),
+			to_ms(level.to),
+			level.n_active,
+			obs.get_workload_name(),
+			obs.get_executor_name(),
+			obs.get_rep_idx()+1))
+	}
+}
+
+func write_concurrency_levels_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_concurrency_levels_header())
+
+	for _, obs := range report.observations {
+		write_concurrency_levels(section, &obs)
+	}
+}
+
+func format_goroutine_samples_header() string {
+	return "Tasks,Moment,Goroutines,Workload,Executor,Rep\n"
+}
+
+func write_goroutine_samples(section *SectionWriter, obs *Observation) {
+
+	for _, sample := range obs.get_goroutine_samples() {
+		section.write_row(fmt.Sprintf("%d,%f,%d,%s,%s,%d\n",
+			obs.count_tasks(),
+			to_ms(sample.moment),
+			sample.n_goroutines,
+			obs.get_workload_name(),
+			obs.get_executor_name(),
+			obs.get_rep_idx()+1))
+	}
+}
+
+func write_goroutine_samples_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_goroutine_samples_header())
+
+	for _, obs := range report.observations {
+		write_goroutine_samples(section, &obs)
+	}
+}
+
+func format_bootstrap_header() string {
+	return "Tasks,Mean low,Mean high,p95 low,p95 high,Profit low,Profit high,Workload,Executor\n"
+}
+
+// Empty profit bounds for a single repetition
+func format_bootstrap(reps Repetitions, seed int64) string {
+
+	mean_low, mean_high := reps.get_bootstrap_task_interval(mean_of, seed)
+	p95_low, p95_high := reps.get_bootstrap_task_interval(p95_of, seed)
+	profit_bounds := ","
+
+	if reps.count_reps() > 1 {
+		profit_low, profit_high := reps.get_bootstrap_interval(metric_concurrency_profit, seed)
+		profit_bounds = fmt.Sprintf("%f%%,%f%%", profit_low*100.0, profit_high*100.0)
+	}
+
+	return fmt.Sprintf("%d,%f,%f,%f,%f,%s,%s,%s\n",
+		reps.get_first().count_tasks(),
+		mean_low,
+		mean_high,
+		p95_low,
+		p95_high,
+		profit_bounds,
+		reps.get_first().get_workload_name(),
+		reps.get_first().get_executor_name())
+}
+
+func write_bootstrap_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_bootstrap_header())
+
+	for idx := range report.observations {
+		if obs := report.get_observation(idx); obs.get_rep_idx() == 0 && obs.get_ci_method() == CI_Bootstrap {
+			section.write_row(format_bootstrap(report.collect_repetitions(obs), report.get_seed()))
+		}
+	}
+}
+
+func format_histograms_header() string {
+	return "Tasks,From,To,Count,Workload,Executor,Rep\n"
+}
+
+// Only buckets holding tasks, to keep the section short with fine bounds
+func write_histogram(section *SectionWriter, obs *Observation) {
+
+	histogram := obs.get_histogram()
+
+	for bucket_idx := 0; bucket_idx < histogram.count_buckets(); bucket_idx++ {
+		if count := histogram.get_bucket_count(bucket_idx); count > 0 {
+			section.write_row(fmt.Sprintf("%d,%d,%s,%d,%s,%s,%d\n",
+				obs.count_tasks(),
+				histogram.get_bucket_from(bucket_idx).Milliseconds(),
+				histogram.format_bucket_to(bucket_idx),
+				count,
+				obs.get_workload_name(),
+				obs.get_executor_name(),
+				obs.get_rep_idx()+1))
+		}
+	}
+}
+
+func write_histograms_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_histograms_header())
+
+	for _, obs := range report.observations {
+		if obs.has_histogram() {
+			write_histogram(section, &obs)
+		}
+	}
+}
+
+func format_cold_starts_header() string {
+	return "Workload,Executor,Cold duration,Warm duration,Difference,Difference %\n"
+}
+
+func format_cold_start(cold_start *ColdStart) string {
+	return fmt.Sprintf("%s,%s,%f,%f,%f,%f\n",
+		cold_start.get_workload_name(),
+		cold_start.get_executor_name(),
+		to_ms(cold_start.get_cold_duration()),
+		to_ms(cold_start.get_warm_duration()),
+		to_ms(cold_start.get_difference()),
+		cold_start.get_relative_difference())
+}
+
+func write_cold_starts_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_cold_starts_header())
+
+	for idx := 0; idx < report.count_cold_starts(); idx++ {
+		section.write_row(format_cold_start(report.get_cold_start(idx)))
+	}
+}
+
+func format_graph_paths_header() string {
+	return "Tasks,Layers,Total work,Critical path,Parallelism,Total duration,Workload,Executor,Rep\n"
+}
+
+func format_graph_paths(obs *Observation) string {
+	return fmt.Sprintf("%d,%d,%f,%f,%f,%f,%s,%s,%d\n",
+		obs.count_tasks(),
+		obs.count_graph_layers(),
+		to_ms(obs.get_total_work()),
+		to_ms(obs.get_critical_path()),
+		obs.get_parallelism(),
+		to_ms(obs.get_total_duration()),
+		obs.get_workload_name(),
+		obs.get_executor_name(),
+		obs.get_rep_idx()+1)
+}
+
+func write_graph_paths_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_graph_paths_header())
+
+	for _, obs := range report.observations {
+		if obs.is_graph() {
+			section.write_row(format_graph_paths(&obs))
+		}
+	}
+}
+
+func format_ramp_throughput_header() string {
+	return "Tasks,Workers,Since,Tasks per second,Workload,Executor,Rep\n"
+}
+
+func write_ramp_throughput(section *SectionWriter, obs *Observation) {
+
+	for n_workers := 1; n_workers <= obs.ramp_workers; n_workers++ {
+		window_start, _ := obs.get_ramp_window(n_workers)
+		section.write_row(fmt.Sprintf("%d,%d,%d,%f,%s,%s,%d\n",
+			obs.count_tasks(),
+			n_workers,
+			window_start.Milliseconds(),
+			obs.get_ramp_throughput(n_workers),
+			obs.get_workload_name(),
+			obs.get_executor_name(),
+			obs.get_rep_idx()+1))
+	}
+}
+
+func write_ramp_throughput_section(w io.Writer, report *Report) {
+
+	section := create_section_writer(w, format_ramp_throughput_header())
+
+	for _, obs := range report.observations {
+		if obs.is_ramped() {
+			write_ramp_throughput(section, &obs)
+		}
+	}
+}
+
+func write_report_header_section(w io.Writer, report *Report) {
+	io.WriteString(w, "Parameter,Value\n"+
+		fmt.Sprintf("CPUs,%d\n", count_cpus())+
+		fmt.Sprintf("GOMAXPROCS,%d\n", report.get_gomaxprocs())+
+		fmt.Sprintf("Async preemption,%s\n", format_switch(!is_async_preempt_off()))+
+		fmt.Sprintf("Clock reading ns,%d\n", report.get_clock_cost().Nanoseconds())+
+		fmt.Sprintf("Goroutine launch ns,%d\n", report.get_launch_cost().Nanoseconds())+
+		format_machine_speed(report)+
+		format_calibration(report)+
+		format_interruption(report)+
+		format_round(report)+
+		fmt.Sprintf("Seed,%d\n", report.get_seed())+
+		format_label_rows(report.get_labels())+
+		format_machine_id(report)+
+		format_machine(report)+
+		"\n")
+}
+
+func format_machine(report *Report) string {
+	if report.is_machine_profiled() {
+		return format_machine_rows(report.get_machine_profile())
+	} else {
+		return ""
+	}
+}
+
+func format_label_rows(labels RunLabels) string {
+
+	rows := ""
+
+	if labels.get_label() != "" {
+		rows += fmt.Sprintf("Label,%s\n", labels.get_label())
+	}
+
+	for _, tag := range labels.get_tags() {
+		rows += fmt.Sprintf("Tag,%s\n", tag)
+	}
+
+	return rows
+}
+
+func format_machine_id(report *Report) string {
+	if report.get_machine_id() != "" {
+		return fmt.Sprintf("Machine profile,%s\n", report.get_machine_id())
+	} else {
+		return ""
+	}
+}
+
+func format_round(report *Report) string {
+	if report.get_round() > 0 {
+		return fmt.Sprintf("Round,%d\nStarted,%s\n", report.get_round(), report.get_started().Format(time.RFC3339))
+	} else {
+		return ""
+	}
+}
+
+func format_calibration(report *Report) string {
+	if report.is_calibrated() {
+		return fmt.Sprintf("Calibrated cycles,%d\nCalibrated task duration ms,%d\n",
+			report.get_calibrated_cycles(),
+			report.get_task_duration().Milliseconds())
+	} else {
+		return ""
+	}
+}
+
+func format_interruption(report *Report) string {
+	if report.is_partial() {
+		return fmt.Sprintf("Partial report,%s\n", report.get_interruption())
+	} else {
+		return ""
+	}
+}
+
+// The machine profile names the speed itself
+func format_machine_speed(report *Report) string {
+	if report.is_normalized() && !report.is_machine_profiled() {
+		return fmt.Sprintf("Cycles per second,%d\n", report.get_cycles_per_sec())
+	} else {
+		return ""
+	}
+}
+
+func format_normalized_header() string {
+	return "Tasks,Mean task duration cycles,Total duration cycles,Workload,Executor,Rep\n"
+}
+
+func format_normalized(report *Report, obs *Observation) string {
+	return fmt.Sprintf("%d,%.0f,%.0f,%s,%s,%d\n",
+		obs.count_tasks(),
+		report.to_cycles(obs.get_mean_task_duration()),
+		report.to_cycles(obs.get_total_duration()),
+		obs.get_workload_name(),
+		obs.get_executor_name(),
+		obs.get_rep_idx()+1)
+}
+
+func write_normalized_section(w io.Writer, report *Report) {
+
+	if !report.is_normalized() {
+		return
+	}
+
+	section := create_section_writer(w, format_normalized_header())
+
+	for idx := range report.observations {
+		section.write_row(format_normalized(report, report.get_observation(idx)))
+	}
+}
+
+func format_formulas_header() string {
+	return "Metric,Formula\n"
+}
+
+func format_quantities_header() string {
+	return "Symbol,Quantity\n"
+}
+
+func format_baselines_header() string {
+	return "Workload,Executor,Series size,Cycles in a task,Baseline tasks,Baseline reps,Baseline ms per cycle\n"
+}
+
+func format_baseline(report *Report, baseline *Observation) string {
+	return fmt.Sprintf("%s,%s,%d,%d,%d,%d,%g\n",
+		baseline.get_workload_name(),
+		baseline.get_executor_name(),
+		baseline.get_series_size(),
+		baseline.get_n_cycles(),
+		baseline.count_tasks(),
+		report.collect_repetitions(baseline).count_reps(),
+		baseline.get_baseline_ms_per_cycle())
+}
+
+// How the metrics of the totals are computed, from the same formulas
+// that compute them
+func write_explanation_section(w io.Writer, report *Report) {
+
+	formulas := create_section_writer(w, format_formulas_header())
+
+	for _, formula := range FORMULAS {
+		formulas.write_row(fmt.Sprintf("%s,%s\n", formula.get_metric_name(), formula.get_text()))
+	}
+
+	quantities := create_section_writer(w, format_quantities_header())
+
+	for _, quantity := range QUANTITIES {
+		quantities.write_row(fmt.Sprintf("%s,%s\n", get_quantity_symbol(quantity), describe_quantity(quantity)))
+	}
+
+	io.WriteString(w, "\n"+format_baselines_header())
+
+	for _, baseline := range report.collect_baselines() {
+		io.WriteString(w, format_baseline(report, baseline))
+	}
+}
+
+// Buffers the writing and gives back the first error of the writer
+func write_report(w io.Writer, report *Report, explained bool) error {
+
+	buffered := bufio.NewWriter(w)
+
+	write_report_header_section(buffered, report)
+	write_observation_totals_section(buffered, report)
+	io.WriteString(buffered, "\n")
+	write_observation_schedules_section(buffered, report)
+	write_stage_times_section(buffered, report)
+	write_repetitions_section(buffered, report)
+	write_normalized_section(buffered, report)
+	write_bootstrap_section(buffered, report)
+	write_ramp_throughput_section(buffered, report)
+	write_littles_law_section(buffered, report)
+	write_concurrency_levels_section(buffered, report)
+	write_goroutine_samples_section(buffered, report)
+	write_histograms_section(buffered, report)
+	write_series_section(buffered, report)
+	write_cold_starts_section(buffered, report)
+	write_graph_paths_section(buffered, report)
+	write_scalability_section(buffered, report)
+
+	if explained {
+		write_explanation_section(buffered, report)
+	}
+
+	return buffered.Flush()
+}
+
+// Rounds after the first one go after the reports of the rounds before
+func append_file(out_file_path string, write func(io.Writer) error) error {
+	return write_file(out_file_path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, write)
+}
+
+func save_file(out_file_path string, write func(io.Writer) error) error {
+	return write_file(out_file_path, os.O_TRUNC|os.O_CREATE|os.O_WRONLY, write)
+}
+
+func save_text(out_file_path string, text string) error {
+	return save_file(out_file_path, func(w io.Writer) error {
+		_, err := io.WriteString(w, text)
+		return err
+	})
+}
+
+// Errors name the file once, followed by the cause
+func write_file(out_file_path string, flags int, write func(io.Writer) error) error {
+
+	if out_file_path == "" {
+		return nil
+	}
+
+	out_file, err := os.OpenFile(out_file_path, flags, 0644)
+
+	if err != nil {
+		return describe_write_error(out_file_path, err)
+	}
+
+	if err := write(out_file); err != nil {
+		out_file.Close()
+		return describe_write_error(out_file_path, err)
+	}
+
+	if err := out_file.Close(); err != nil {
+		return describe_write_error(out_file_path, err)
+	}
+
+	return nil
+}
+
+func describe_write_error(path string, err error) error {
+
+	var path_err *fs.PathError
+
+	if errors.As(err, &path_err) {
+		err = path_err.Err
+	}
+
+	return fmt.Errorf("cannot write %s: %w", path, err)
+}
+
+// Checked before a run, so that a mistyped path does not lose it
+// at the end; the file itself may not exist yet
+func is_dir_existing(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// Comparing with a baseline report
+
+// Observations of a baseline are matched by the experiment and the number of tasks
+type BaselineKey struct {
+	workload_name  string
+	executor_name  string
+	thread_locked  bool
+	n_tasks        int
+	series_size    int
+	n_cycles       int
+	experiment_idx int
+}
+
+func create_baseline_key(obs *Observation) BaselineKey {
+	return BaselineKey{obs.get_workload_name(), obs.get_executor_name(), obs.is_thread_locked(), obs.count_tasks(), obs.get_series_size(), obs.get_n_cycles(), obs.get_experiment_idx()}
+}
+
+// Means over repetitions
+type BaselineEntry struct {
+	mean_task_duration float64
+	profit             float64
+	n_reps             int
+}
+
+func (e *BaselineEntry) add(mean_task_duration, profit float64) {
+	e.mean_task_duration = (e.mean_task_duration*float64(e.n_reps) + mean_task_duration) / float64(e.n_reps+1)
+	e.profit = (e.profit*float64(e.n_reps) + profit) / float64(e.n_reps+1)
+	e.n_reps++
+}
+
+// Columns of the totals section a baseline is read from, looked up by
+// name, so that columns added later leave the baseline readable
+var BASELINE_COLUMNS = []string{
+	"Tasks", "Mean task duration", "Profit", "Workload", "Executor",
+	"Locked threads", "Series size", "Cycles in a task", "Experiment",
+}
+
+// Reads the fields of a totals row by the names of the columns;
+// the first missing or malformed field spoils the whole row
+type TotalsRow struct {
+	columns map[string]int
+	fields  []string
+	err     error
+}
+
+func (r *TotalsRow) get_string(column string) string {
+
+	idx, ok := r.columns[column]
+
+	if !ok || idx >= len(r.fields) {
+		if r.err == nil {
+			r.err = fmt.Errorf("no %s in the row", column)
+		}
+		return ""
+	}
+
+	return r.fields[idx]
+}
+
+func (r *TotalsRow) get_int(column string) int {
+
+	value := r.get_string(column)
+	i, err := strconv.Atoi(value)
+
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("%s %q is not a whole number", column, value)
+	}
+
+	return i
+}
+
+// Percentages are read without the percent sign
+func (r *TotalsRow) get_float(column string) float64 {
+
+	value := r.get_string(column)
+	f, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("%s %q is not a number", column, value)
+	}
+
+	return f
+}
+
+func (r *TotalsRow) get_bool(column string) bool {
+
+	value := r.get_string(column)
+	b, err := strconv.ParseBool(value)
+
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("%s %q is neither true nor false", column, value)
+	}
+
+	return b
+}
+
+func split_totals_fields(line string) []string {
+
+	fields := strings.Split(line, ",")
+
+	for idx := range fields {
+		fields[idx] = strings.TrimSpace(fields[idx])
+	}
+
+	return fields
+}
+
+// Reads the totals section of a report saved earlier
+func load_baseline(path string) (map[BaselineKey]BaselineEntry, error) {
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	baseline := map[BaselineKey]BaselineEntry{}
+	var columns map[string]int = nil
+
+	for line_idx, line := range strings.Split(string(content), "\n") {
+
+		if columns == nil && strings.HasPrefix(line, "Tasks,Mean task duration,") {
+
+			columns = map[string]int{}
+
+			for idx, name := range split_totals_fields(line) {
+				columns[name] = idx
+			}
+
+			for _, name := range BASELINE_COLUMNS {
+				if _, ok := columns[name]; !ok {
+					return nil, fmt.Errorf("%s: the totals have no %s column, the report may come from an earlier version", path, name)
+				}
+			}
+
+			continue
+		}
+
+		if columns == nil {
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+
+		row := TotalsRow{columns, split_totals_fields(line), nil}
+		key := BaselineKey{
+			row.get_string("Workload"),
+			row.get_string("Executor"),
+			row.get_bool("Locked threads"),
+			row.get_int("Tasks"),
+			row.get_int("Series size"),
+			row.get_int("Cycles in a task"),
+			row.get_int("Experiment")}
+		mean_task_duration := row.get_float("Mean task duration")
+		profit := row.get_float("Profit")
+
+		if row.err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line_idx+1, row.err)
+		}
+
+		entry := baseline[key]
+		entry.add(mean_task_duration, profit)
+		baseline[key] = entry
+	}
+
+	if len(baseline) == 0 {
+		return nil, fmt.Errorf("%s: no observation totals found", path)
+	}
+
+	return baseline, nil
+}
+
+// A regression is a mean task duration longer by more than tolerance percent
+// or a profit lower by more than tolerance percentage points; also gives
+// the number of observations the baseline had an entry for
+func find_regressions(report *Report, baseline map[BaselineKey]BaselineEntry, tolerance float64) ([]string, int) {
+
+	regressions := []string{}
+	n_matched := 0
+
+	for idx := range report.observations {
+
+		obs := report.get_observation(idx)
+
+		if obs.get_rep_idx() != 0 {
+			continue
+		}
+
+		entry, ok := baseline[create_baseline_key(obs)]
+
+		if !ok {
+			continue
+		}
+
+		n_matched++
+		reps := report.collect_repetitions(obs)
+		mean_task_duration := reps.get_mean(metric_mean_task_duration)
+		profit := reps.get_mean(metric_concurrency_profit) * 100.0
+
+		if mean_task_duration > entry.mean_task_duration*(1+tolerance/100) {
+			regressions = append(regressions, fmt.Sprintf(
+				"%s, %s, %d tasks: mean task duration %.1f ms, baseline %.1f ms",
+				obs.get_workload_name(), obs.get_executor_name(), obs.count_tasks(),
+				mean_task_duration, entry.mean_task_duration))
+		}
+
+		if profit < entry.profit-tolerance {
+			regressions = append(regressions, fmt.Sprintf(
+				"%s, %s, %d tasks: profit %.1f%%, baseline %.1f%%",
+				obs.get_workload_name(), obs.get_executor_name(), obs.count_tasks(),
+				profit, entry.profit))
+		}
+	}
+
+	return regressions, n_matched
+}
+
+// Comparing two saved reports
+
+// Task durations of two reports are matched by the experiment and the number of tasks
+type SampleKey struct {
+	workload_name string
+	executor_name string
+	n_tasks       int
+}
+
+func (k SampleKey) is_less(other SampleKey) bool {
+	if k.workload_name != other.workload_name {
+		return k.workload_name < other.workload_name
+	} else if k.executor_name != other.executor_name {
+		return k.executor_name < other.executor_name
+	} else {
+		return k.n_tasks < other.n_tasks
+	}
+}
+
+type SampleComparison struct {
+	key    SampleKey
+	mean_a float64
+	mean_b float64
+	t      float64
+	p      float64
+}
+
+// Change of the mean from the first report to the second one, in percent
+func (c SampleComparison) get_relative_difference() float64 {
+	if c.mean_a > 0 {
+		return (c.mean_b - c.mean_a) * 100 / c.mean_a
+	} else {
+		return 0
+	}
+}
+
+func (c SampleComparison) is_significant(alpha float64) bool {
+	return c.p < alpha
+}
+
+// Reads durations of completed tasks from the schedule section of a saved report
+func load_task_durations(path string) (map[SampleKey][]float64, error) {
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	samples := map[SampleKey][]float64{}
+	in_schedules := false
+
+	for _, line := range strings.Split(string(content), "\n") {
+
+		if strings.HasPrefix(line, "Tasks,Task,Started,") {
+			in_schedules = true
+			continue
+		}
+
+		if !in_schedules {
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+
+		fields := strings.Split(line, ",")
+
+		if len(fields) < 9 {
+			return nil, fmt.Errorf("%s: unexpected schedule row %q", path, line)
+		}
+
+		if fields[7] != format_task_status(TS_Done) {
+			continue
+		}
+
+		key := SampleKey{fields[6], fields[8], parse_int(fields[0])}
+		samples[key] = append(samples[key], parse_float(fields[4]))
+	}
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("%s: no task schedules found", path)
+	}
+
+	return samples, nil
+}
+
+// Tests every experiment and number of tasks found in both reports
+func compare_samples(samples_a, samples_b map[SampleKey][]float64) []SampleComparison {
+
+	comparisons := []SampleComparison{}
+
+	for key, durations_a := range samples_a {
+		if durations_b, ok := samples_b[key]; ok {
+			t, _, p := welch_t_test(durations_a, durations_b)
+			comparisons = append(comparisons,
+				SampleComparison{key, mean_of(durations_a), mean_of(durations_b), t, p})
+		}
+	}
+
+	sort.Slice(comparisons, func(i, j int) bool {
+		return comparisons[i].key.is_less(comparisons[j].key)
+	})
+
+	return comparisons
+}
+
+func compare_reports(path_a, path_b string, alpha float64) error {
+
+	samples_a, err := load_task_durations(path_a)
+
+	if err != nil {
+		return err
+	}
+
+	samples_b, err := load_task_durations(path_b)
+
+	if err != nil {
+		return err
+	}
+
+	comparisons := compare_samples(samples_a, samples_b)
+
+	if len(comparisons) == 0 {
+		return fmt.Errorf("%s and %s have no observations in common", path_a, path_b)
+	}
+
+	print_comparisons(path_a, path_b, comparisons, alpha)
+
+	return nil
+}
+
+// Labeling runs
+
+// A run may carry a label like before-fix and tags like branch=main,
+// which its report keeps in the header for list and compare to select by
+type RunLabels struct {
+	label string
+	tags  []string
+}
+
+func create_run_labels(label string, tags []string) RunLabels {
+	return RunLabels{label, tags}
+}
+
+func (l RunLabels) get_label() string {
+	return l.label
+}
+
+func (l RunLabels) get_tags() []string {
+	return l.tags
+}
+
+func (l RunLabels) has_tag(tag string) bool {
+	return slices.Contains(l.get_tags(), tag)
+}
+
+// Runs match a filter with their label, when the filter has one, and every tag of it
+func (l RunLabels) is_matching(filter RunLabels) bool {
+
+	if filter.get_label() != "" && filter.get_label() != l.get_label() {
+		return false
+	}
+
+	for _, tag := range filter.get_tags() {
+		if !l.has_tag(tag) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// A label may not break the CSV row it goes to
+func is_label_valid(label string) bool {
+	return !strings.ContainsAny(label, ",\n")
+}
+
+// A tag goes as key=value with a key and without commas
+func is_tag_valid(tag string) bool {
+	key, _, ok := strings.Cut(tag, "=")
+	return ok && key != "" && !strings.Contains(tag, ",")
+}
+
+// The operands of compare may select the latest report by label:<Text> or tag:<key=value>
+func parse_report_selector(operand string) (RunLabels, bool) {
+	if label, ok := strings.CutPrefix(operand, "label:"); ok && label != "" && is_label_valid(label) {
+		return create_run_labels(label, nil), true
+	} else if tag, ok := strings.CutPrefix(operand, "tag:"); ok && is_tag_valid(tag) {
+		return create_run_labels("", []string{tag}), true
+	} else {
+		return RunLabels{}, false
+	}
+}
+
+// Adds the tags of another filter, so that both apply
+func (l RunLabels) merge(other RunLabels) RunLabels {
+	return create_run_labels(l.get_label(), append(slices.Clone(l.get_tags()), other.get_tags()...))
+}
+
+type SavedReport struct {
+	path     string
+	labels   RunLabels
+	modified time.Time
+}
+
+func (r SavedReport) get_path() string {
+	return r.path
+}
+
+func (r SavedReport) get_labels() RunLabels {
+	return r.labels
+}
+
+func (r SavedReport) get_modified() time.Time {
+	return r.modified
+}
+
+// Reads the label and the tags from the header of a saved report,
+// false for a file that is no report
+func load_run_labels(path string) (RunLabels, bool, error) {
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return RunLabels{}, false, err
+	}
+
+	lines := strings.Split(string(content), "\n")
+
+	if lines[0] != "Parameter,Value" {
+		return RunLabels{}, false, nil
+	}
+
+	labels := create_run_labels("", []string{})
+
+	for _, line := range lines[1:] {
+
+		if strings.TrimSpace(line) == "" {
+			break
+		}
+
+		if label, ok := strings.CutPrefix(line, "Label,"); ok {
+			labels.label = label
+		} else if tag, ok := strings.CutPrefix(line, "Tag,"); ok {
+			labels.tags = append(labels.tags, tag)
+		}
+	}
+
+	return labels, true, nil
+}
+
+// Reports saved in the directory that match the filter, oldest first
+func find_reports(dir string, filter RunLabels) ([]SavedReport, error) {
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*.csv"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	reports := []SavedReport{}
+
+	for _, path := range paths {
+
+		labels, ok, err := load_run_labels(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok || !labels.is_matching(filter) {
+			continue
+		}
+
+		info, err := os.Stat(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		reports = append(reports, SavedReport{path, labels, info.ModTime()})
+	}
+
+	sort.SliceStable(reports, func(i, j int) bool {
+		return reports[i].get_modified().Before(reports[j].get_modified())
+	})
+
+	return reports, nil
+}
+
+// A selector stands for the latest matching report of the directory, anything else for a path
+func resolve_report_path(dir, operand string, filter RunLabels) (string, error) {
+
+	selector, ok := parse_report_selector(operand)
+
+	if !ok {
+		return operand, nil
+	}
+
+	reports, err := find_reports(dir, selector.merge(filter))
+
+	if err != nil {
+		return "", err
+	}
+
+	if len(reports) == 0 {
+		return "", fmt.Errorf("no report in %s matches %s", dir, operand)
+	}
+
+	return reports[len(reports)-1].get_path(), nil
+}
+
+func list_reports(dir string, filter RunLabels) error {
+
+	reports, err := find_reports(dir, filter)
+
+	if err != nil {
+		return err
+	}
+
+	print_saved_reports(dir, reports)
+
+	return nil
+}
+
+// Checkpointing observations
+
+// A checkpoint file starts with the arguments of the run and holds
+// a block of lines per observation completed: the observation itself,
+// its tasks, goroutine samples, histogram, and streamed statistics,
+// closed by an end line. Fields are separated by tabs, strings quoted
+const CHECKPOINT_VERSION = 4
+
+type CheckpointKey struct {
+	run_idx int
+	n_tasks int
+	rep_idx int
+}
+
+// Appends completed observations to the file and gives back those
+// of an interrupted run; a nil checkpoint keeps and restores nothing
+type Checkpoint struct {
+	path     string
+	out_file *os.File
+	buffered *bufio.Writer
+	restored map[CheckpointKey]Observation
+	n_runs   int
+}
+
+func (c *Checkpoint) get_path() string {
+	return c.path
+}
+
+func (c *Checkpoint) count_restored() int {
+	if c == nil {
+		return 0
+	} else {
+		return len(c.restored)
+	}
+}
+
+// Runs are the experiments in the order they start, the same
+// from one attempt to the next with the same arguments
+func (c *Checkpoint) begin_run() int {
+
+	if c == nil {
+		return 0
+	}
+
+	c.n_runs++
+
+	return c.n_runs - 1
+}
+
+func (c *Checkpoint) restore(run_idx, n_tasks, rep_idx int) (Observation, bool) {
+
+	if c == nil {
+		return Observation{}, false
+	}
+
+	obs, ok := c.restored[CheckpointKey{run_idx, n_tasks, rep_idx}]
+
+	return obs, ok
+}
+
+func (c *Checkpoint) is_run_restored(run_idx int, task_counts []int, n_reps int) bool {
+
+	for _, n_tasks := range task_counts {
+		for rep_idx := 0; rep_idx < n_reps; rep_idx++ {
+			if _, ok := c.restore(run_idx, n_tasks, rep_idx); !ok {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// Writes the block through a buffer and syncs it, so that a run killed
+// at any moment leaves at most one incomplete block behind
+func (c *Checkpoint) save(run_idx int, obs Observation) error {
+
+	if c == nil {
+		return nil
+	}
+
+	write_checkpoint_block(c.buffered, run_idx, obs)
+
+	if err := c.buffered.Flush(); err != nil {
+		return err
+	}
+
+	return c.out_file.Sync()
+}
+
+func (c *Checkpoint) close() {
+	if c != nil {
+		c.out_file.Close()
+	}
+}
+
+// A completed run needs no checkpoint anymore
+func (c *Checkpoint) remove() {
+	if c != nil {
+		c.close()
+		os.Remove(c.get_path())
+	}
+}
+
+// Starts a new file unless resuming, which requires the same arguments
+// as the interrupted run; returns nil without a path
+func open_checkpoint(path string, fingerprint string, resume bool) (*Checkpoint, error) {
+
+	if path == "" {
+		return nil, nil
+	}
+
+	restored := map[CheckpointKey]Observation{}
+
+	if resume {
+
+		content, err := os.ReadFile(path)
+
+		if err != nil {
+			return nil, err
+		}
+
+		restored, err = parse_checkpoint(string(content), fingerprint)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	out_file, err := os.Create(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoint := &Checkpoint{path, out_file, bufio.NewWriter(out_file), restored, 0}
+
+	// Rewriting the blocks restored drops an incomplete one at the end
+	write_checkpoint_fields(checkpoint.buffered, "checkpoint", CHECKPOINT_VERSION, fingerprint)
+
+	for key, obs := range restored {
+		write_checkpoint_block(checkpoint.buffered, key.run_idx, obs)
+	}
+
+	if err := checkpoint.buffered.Flush(); err != nil {
+		out_file.Close()
+		return nil, err
+	}
+
+	return checkpoint, nil
+}
+
+func format_checkpoint_value(value any) string {
+	switch value := value.(type) {
+	case string:
+		return strconv.Quote(value)
+	case error:
+		return strconv.Quote(value.Error())
+	case nil:
+		return strconv.Quote("")
+	case bool:
+		return strconv.FormatBool(value)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case time.Duration:
+		return strconv.FormatInt(int64(value), 10)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func write_checkpoint_fields(w io.Writer, values ...any) {
+
+	for idx, value := range values {
+		if idx > 0 {
+			io.WriteString(w, "\t")
+		}
+		io.WriteString(w, format_checkpoint_value(value))
+	}
+
+	io.WriteString(w, "\n")
+}
+
+func format_checkpoint_durations(durations []time.Duration) string {
+
+	fields := []string{}
+
+	for _, duration := range durations {
+		fields = append(fields, strconv.FormatInt(int64(duration), 10))
+	}
+
+	return strings.Join(fields, ",")
+}
+
+func write_checkpoint_block(w io.Writer, run_idx int, obs Observation) {
+
+	write_checkpoint_fields(
+		w, "observation", run_idx, obs.rep_idx,
+		obs.workload_name, obs.executor_name, obs.first_failure,
+		obs.thread_locked, obs.ramp_workers, obs.ramp_period, obs.bound,
+		obs.series_size, obs.n_noise, obs.graph_layers, obs.critical_path,
+		obs.offered_rate, obs.outlier_rule, obs.max_cv, obs.energy_uj, obs.ci_method,
+		obs.n_gc, obs.gc_pause, obs.heap_growth, obs.alloc_bytes, obs.n_mallocs,
+		obs.perf_counts.instructions, obs.perf_counts.cycles,
+		obs.perf_counts.cache_misses, obs.perf_counts.context_switches,
+		obs.voluntary_cs, obs.involuntary_cs,
+		obs.threads_before, obs.threads_after, obs.threads_created, obs.n_cycles,
+		obs.experiment_idx, obs.gomaxprocs)
+
+	for _, task := range obs.tasks {
+		write_checkpoint_fields(
+			w, "task", task.idx, task.n_cycles, task.start, task.duration,
+			format_checkpoint_durations(task.stage_times), task.status, task.err,
+			task.launched, task.n_runs, task.sched_latency, task.series_idx,
+			task.user_time, task.system_time, task.alloc_bytes, task.n_allocs)
+	}
+
+	for _, sample := range obs.goroutine_samples {
+		write_checkpoint_fields(w, "sample", sample.moment, sample.n_goroutines)
+	}
+
+	if histogram := obs.histogram; histogram != nil {
+
+		counts := []string{}
+
+		for _, count := range histogram.counts {
+			counts = append(counts, strconv.FormatInt(count, 10))
+		}
+
+		write_checkpoint_fields(w, "histogram", format_checkpoint_durations(histogram.bounds), strings.Join(counts, ","))
+	}
+
+	if stats := obs.stats; stats != nil {
+
+		n_by_status := []string{}
+
+		for status, count := range stats.n_by_status {
+			n_by_status = append(n_by_status, fmt.Sprintf("%d:%d", status, count))
+		}
+
+		write_checkpoint_fields(
+			w, "stats", stats.n_tasks, stats.n_registered, strings.Join(n_by_status, ","),
+			stats.n_measured, stats.mean, stats.m2, stats.sum_duration, stats.sum_measured,
+			stats.min_duration, stats.max_duration, stats.earliest_start, stats.latest_finish,
+			stats.earliest_launch, stats.latest_launch, stats.nominal_cycles,
+			stats.completed_cycles, stats.completed_runs, stats.done_runs,
+			stats.n_cpu_timed, stats.cpu_timed_wall, stats.sum_user_time, stats.sum_system_time)
+	}
+
+	write_checkpoint_fields(w, "end")
+}
+
+// Reads the fields of a line one after another; the first malformed
+// field spoils the whole line
+type CheckpointFields struct {
+	fields []string
+	idx    int
+	err    error
+}
+
+func (f *CheckpointFields) next() string {
+
+	if f.idx >= len(f.fields) {
+		f.err = errors.New("too few fields")
+		return ""
+	}
+
+	f.idx++
+
+	return f.fields[f.idx-1]
+}
+
+func (f *CheckpointFields) next_int64() int64 {
+
+	value, err := strconv.ParseInt(f.next(), 10, 64)
+
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+
+	return value
+}
+
+func (f *CheckpointFields) next_int() int {
+	return int(f.next_int64())
+}
+
+func (f *CheckpointFields) next_duration() time.Duration {
+	return time.Duration(f.next_int64())
+}
+
+func (f *CheckpointFields) next_float() float64 {
+
+	value, err := strconv.ParseFloat(f.next(), 64)
+
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+
+	return value
+}
+
+func (f *CheckpointFields) next_bool() bool {
+
+	value, err := strconv.ParseBool(f.next())
+
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+
+	return value
+}
+
+func (f *CheckpointFields) next_string() string {
+
+	value, err := strconv.Unquote(f.next())
+
+	if err != nil && f.err == nil {
+		f.err = err
+	}
+
+	return value
+}
+
+// nil for an empty message
+func (f *CheckpointFields) next_error() error {
+	if message := f.next_string(); message == "" {
+		return nil
+	} else {
+		return errors.New(message)
+	}
+}
+
+func (f *CheckpointFields) next_durations() []time.Duration {
+
+	durations := []time.Duration{}
+
+	for _, field := range strings.Split(f.next_string(), ",") {
+		if field != "" {
+			value, err := strconv.ParseInt(field, 10, 64)
+			if err != nil && f.err == nil {
+				f.err = err
+			}
+			durations = append(durations, time.Duration(value))
+		}
+	}
+
+	return durations
+}
+
+func (f *CheckpointFields) next_int64s() []int64 {
+
+	values := []int64{}
+
+	for _, field := range strings.Split(f.next_string(), ",") {
+		if field != "" {
+			value, err := strconv.ParseInt(field, 10, 64)
+			if err != nil && f.err == nil {
+				f.err = err
+			}
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+func (f *CheckpointFields) next_status_counts() map[TaskStatus]int {
+
+	n_by_status := map[TaskStatus]int{}
+
+	for _, field := range strings.Split(f.next_string(), ",") {
+		if status, count, ok := strings.Cut(field, ":"); ok {
+			n_by_status[parse_int(status)] = parse_int(count)
+		}
+	}
+
+	return n_by_status
+}
+
+func parse_checkpoint_observation(f *CheckpointFields) (int, Observation) {
+
+	run_idx := f.next_int()
+
+	obs := create_observation("", "", 0)
+	obs.rep_idx = f.next_int()
+	obs.workload_name = f.next_string()
+	obs.executor_name = f.next_string()
+	obs.first_failure = f.next_error()
+	obs.thread_locked = f.next_bool()
+	obs.ramp_workers = f.next_int()
+	obs.ramp_period = f.next_duration()
+	obs.bound = f.next_duration()
+	obs.series_size = f.next_int()
+	obs.n_noise = f.next_int()
+	obs.graph_layers = f.next_int()
+	obs.critical_path = f.next_duration()
+	obs.offered_rate = f.next_float()
+	obs.outlier_rule = f.next_int()
+	obs.max_cv = f.next_float()
+	obs.energy_uj = f.next_int64()
+	obs.ci_method = f.next_int()
+	obs.n_gc = f.next_int()
+	obs.gc_pause = f.next_duration()
+	obs.heap_growth = f.next_int64()
+	obs.alloc_bytes = f.next_int64()
+	obs.n_mallocs = f.next_int64()
+	obs.perf_counts = PerfCounts{f.next_int64(), f.next_int64(), f.next_int64(), f.next_int64()}
+	obs.voluntary_cs = f.next_int64()
+	obs.involuntary_cs = f.next_int64()
+	obs.threads_before = f.next_int()
+	obs.threads_after = f.next_int()
+	obs.threads_created = f.next_int()
+	obs.n_cycles = f.next_int()
+	obs.experiment_idx = f.next_int()
+	obs.gomaxprocs = f.next_int()
+
+	return run_idx, obs
+}
+
+func parse_checkpoint_task(f *CheckpointFields) Task {
+	return Task{
+		f.next_int(),
+		f.next_int(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_durations(),
+		f.next_int(),
+		f.next_error(),
+		f.next_duration(),
+		f.next_int(),
+		f.next_duration(),
+		f.next_int(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_int64(),
+		f.next_int64()}
+}
+
+func parse_checkpoint_stats(f *CheckpointFields) *TaskStats {
+	return &TaskStats{
+		&sync.Mutex{},
+		f.next_int(),
+		f.next_int(),
+		f.next_status_counts(),
+		f.next_int(),
+		f.next_float(),
+		f.next_float(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_int(),
+		f.next_int(),
+		f.next_int(),
+		f.next_int(),
+		f.next_int(),
+		f.next_duration(),
+		f.next_duration(),
+		f.next_duration()}
+}
+
+// Observations of blocks without an end line are left out
+func parse_checkpoint(content string, fingerprint string) (map[CheckpointKey]Observation, error) {
+
+	restored := map[CheckpointKey]Observation{}
+	lines := strings.Split(content, "\n")
+
+	header := &CheckpointFields{strings.Split(lines[0], "\t"), 0, nil}
+
+	if header.next_string() != "checkpoint" || header.next_int() != CHECKPOINT_VERSION || header.err != nil {
+		return nil, errors.New("not a checkpoint of this version")
+	} else if header.next_string() != fingerprint || header.err != nil {
+		return nil, errors.New("the checkpoint was made with different arguments")
+	}
+
+	var run_idx int
+	var obs *Observation = nil
+
+	for line_idx, line := range lines[1:] {
+
+		if line == "" {
+			continue
+		}
+
+		f := &CheckpointFields{strings.Split(line, "\t"), 0, nil}
+
+		switch record := f.next_string(); {
+		case record == "observation":
+			idx, parsed := parse_checkpoint_observation(f)
+			run_idx, obs = idx, &parsed
+		case obs == nil:
+			f.err = fmt.Errorf("%s outside an observation", record)
+		case record == "task":
+			obs.tasks = append(obs.tasks, parse_checkpoint_task(f))
+		case record == "sample":
+			obs.goroutine_samples = append(obs.goroutine_samples, GoroutineSample{f.next_duration(), f.next_int()})
+		case record == "histogram":
+			obs.histogram = &Histogram{f.next_durations(), f.next_int64s()}
+		case record == "stats":
+			obs.stats = parse_checkpoint_stats(f)
+		case record == "end":
+			restored[CheckpointKey{run_idx, obs.count_tasks(), obs.get_rep_idx()}] = *obs
+			obs = nil
+		default:
+			f.err = fmt.Errorf("unknown record %s", record)
+		}
+
+		if f.err != nil {
+			return nil, fmt.Errorf("line %d: %w", line_idx+2, f.err)
+		}
+	}
+
+	return restored, nil
+}
+
+// Planning a measurement without running it
+
+// A dry run trades the precision of the calibration for a quick answer
+const DRY_RUN_CALIBRATION = 100 * time.Millisecond
+
+// Sleeping workloads block for a microsecond per cycle, other ones
+// are assumed to loop at the calibrated speed
+func estimate_task_duration(setup Setup, cycles_per_sec int) time.Duration {
+	switch setup.get_workload().get_kind() {
+	case WL_GoSleep, WL_CgoSleep:
+		return time.Duration(setup.get_n_cycles()) * time.Microsecond
+	default:
+		return time.Duration(float64(setup.get_n_cycles()) / float64(cycles_per_sec) * float64(time.Second))
+	}
+}
+
+// Tasks run in series of series_size, and tasks of a series share gomaxprocs
+// threads unless they sleep; overheads of executors are left out
+func estimate_observation(setup Setup, n_tasks, gomaxprocs int, task_duration time.Duration) time.Duration {
+
+	if setup.is_bounded() {
+		return setup.get_bound()
+	}
+
+	duration := time.Duration(0)
+
+	for n_left := n_tasks; n_left > 0; n_left -= setup.get_series_size() {
+
+		n_concurrent := min(n_left, setup.get_series_size())
+
+		switch setup.get_workload().get_kind() {
+		case WL_GoSleep, WL_CgoSleep:
+			duration += task_duration
+		default:
+			duration += task_duration * time.Duration((n_concurrent+gomaxprocs-1)/gomaxprocs)
+		}
+	}
+
+	return duration
+}
+
+type PlanEntry struct {
+	setup          Setup
+	n_observations int
+	duration       time.Duration
+}
+
+func (e PlanEntry) get_setup() Setup {
+	return e.setup
+}
+
+func (e PlanEntry) count_observations() int {
+	return e.n_observations
+}
+
+func (e PlanEntry) get_duration() time.Duration {
+	return e.duration
+}
+
+// Counts warm-up and cold start observations along with the measured ones
+func plan_setup(setup Setup, task_counts []int, gomaxprocs, cycles_per_sec int) PlanEntry {
+
+	task_duration := estimate_task_duration(setup, cycles_per_sec)
+	n_observations := 0
+	duration := time.Duration(0)
+
+	if setup.is_cold_warm() {
+		n_observations += 2
+		duration += 2 * estimate_observation(setup, 1, gomaxprocs, task_duration)
+	}
+
+	n_observations += setup.count_warmups()
+	duration += time.Duration(setup.count_warmups()) * estimate_observation(setup, setup.get_series_size(), gomaxprocs, task_duration)
+
+	for _, n_tasks := range task_counts {
+		n_observations += setup.count_reps()
+		duration += time.Duration(setup.count_reps()) * estimate_observation(setup, n_tasks, gomaxprocs, task_duration)
+	}
+
+	return PlanEntry{setup, n_observations, duration}
+}
+
+func plan_measurement(setups []Setup, task_counts []int, gomaxprocs, cycles_per_sec int) []PlanEntry {
+
+	entries := []PlanEntry{}
+
+	for _, setup := range setups {
+		entries = append(entries, plan_setup(setup, task_counts, gomaxprocs, cycles_per_sec))
+	}
+
+	return entries
+}
+
+// Comparing executors runs every setup with goroutines and then with a pool
+func pair_executors(setups []Setup) []Setup {
+
+	paired := []Setup{}
+
+	for _, setup := range setups {
+		for _, executor := range []Executor{EX_Batch, EX_Pool} {
+			setup.set_executor(executor)
+			paired = append(paired, setup)
+		}
+	}
+
+	return paired
+}
+
+// Performing observations
+
+func check_sysparams(ctx context.Context) MachineProfile {
+
+	print_sysparams_header()
+	print_cpus(count_cpus())
+	print_energy_counters(open_energy_meter() != nil)
+
+	clock_cost := measure_clock_cost()
+	print_clock_cost(clock_cost)
+
+	launch_cost := measure_launch_cost()
+	print_launch_cost(launch_cost)
+
+	cycles_per_sec := count_cycles_per_sec(ctx)
+	print_cycles_per_sec(cycles_per_sec)
+
+	print_sysparams_footer()
+
+	profile := profile_machine(clock_cost, launch_cost, cycles_per_sec)
+	print_machine(profile)
+
+	return profile
+}
+
+// Saves the machine profile too when profile_path is given,
+// unless the calibration was interrupted
+func test_sysparams(ctx context.Context, profile_path string) error {
+
+	profile := check_sysparams(ctx)
+
+	if profile_path == "" || ctx.Err() != nil {
+		return nil
+	}
+
+	if err := save_text(profile_path, format_machine_profile(profile)); err != nil {
+		print_unsaved_text(profile_path, format_machine_profile(profile))
+		return err
+	}
+
+	print_machine_profile_saved(profile.get_id(), profile_path)
+
+	return nil
+}
+
+// Throwaway observations of a full series let CPU frequency, caches,
+// and the scheduler settle before the baseline is measured
+func warm_up(ctx context.Context, setup Setup) {
+
+	// Reporters hear only of the observations that go into the report
+	setup.set_reporters(nil)
+
+	for warmup_idx := 0; warmup_idx < setup.count_warmups() && ctx.Err() == nil; warmup_idx++ {
+		observe(ctx, setup.get_series_size(), setup)
+	}
+}
+
+// Shows how much the very first observation of one task, the baseline
+// of cost and profit, would be inflated by starting cold
+func measure_cold_start(ctx context.Context, report *Report, setup Setup) {
+
+	setup.set_reporters(nil)
+
+	cold := observe(ctx, 1, setup)
+	warm := observe(ctx, 1, setup)
+
+	if ctx.Err() == nil {
+		report.register_cold_start(create_cold_start(cold, warm))
+		print_cold_start(report.get_cold_start(report.count_cold_starts() - 1))
+	}
+}
+
+func test_concurrency_profit(ctx context.Context, report *Report, task_counts []int, setup Setup) error {
+
+	start := now()
+	first_idx := report.count_observations()
+	run_idx := setup.get_checkpoint().begin_run()
+
+	var failure error = nil
+
+	print_workload_title(setup)
+
+	if setup.is_cold_warm() {
+		measure_cold_start(ctx, report, setup)
+	}
+
+	if setup.count_warmups() > 0 && !setup.get_checkpoint().is_run_restored(run_idx, task_counts, setup.count_reps()) {
+		print_warmup(setup.count_warmups())
+		warm_up(ctx, setup)
+	}
+
+	if setup.is_bounded() {
+		print_bounded_header(setup.get_bound())
+	} else {
+		print_profit_header()
+	}
+
+	for count_idx := 0; count_idx < len(task_counts) && ctx.Err() == nil && failure == nil; count_idx++ {
+
+		n_tasks := task_counts[count_idx]
+
+		failure = setup.get_tracer().begin_observations(n_tasks)
+
+		for rep_idx := 0; rep_idx < setup.count_reps() && ctx.Err() == nil && failure == nil; rep_idx++ {
+
+			if obs, ok := setup.get_checkpoint().restore(run_idx, n_tasks, rep_idx); ok {
+				report.register_observation(obs)
+				setup.get_progress().skip(n_tasks)
+				setup.get_reporters().OnObservationDone(setup, report, report.get_last_observation())
+				continue
+			}
+
+			setup.get_reporters().OnObservationStart(setup, n_tasks)
+
+			obs := observe(ctx, n_tasks, setup)
+			obs.set_rep_idx(rep_idx)
+
+			// An observation cut short is measured again on resuming
+			if ctx.Err() == nil {
+				if err := setup.get_checkpoint().save(run_idx, obs); err != nil {
+					failure = err
+				}
+			}
+
+			report.register_observation(obs)
+			setup.get_progress().advance(n_tasks)
+			setup.get_reporters().OnObservationDone(setup, report, report.get_last_observation())
+
+			if setup.is_fail_fast() {
+				failure = obs.get_first_failure()
+			}
+		}
+
+		setup.get_tracer().end_observations(n_tasks)
+
+		if count_idx+1 < len(task_counts) && is_next_cpu_block(n_tasks, task_counts[count_idx+1]) && ctx.Err() == nil && failure == nil {
+			print_profit_separator()
+		}
+
+		setup.get_progress().draw()
+	}
+
+	setup.get_progress().clear()
+
+	print_profit_footer()
+
+	if n_dominated := report.count_overhead_dominated(first_idx); n_dominated > 0 {
+		print_overhead_warning(report, n_dominated)
+	}
+
+	if !setup.is_bounded() {
+		if !setup.is_streaming() {
+			print_percentiles(report, first_idx)
+		}
+		print_throughput(report, first_idx)
+		if !setup.is_streaming() {
+			print_makespan(report, first_idx)
+		}
+	}
+
+	if report.count_observations() > first_idx && report.get_last_observation().is_energy_measured() {
+		print_energy(report, first_idx)
+	}
+
+	if report.count_observations() > first_idx && report.get_last_observation().is_cpu_timed() {
+		print_cpu_times(report, first_idx)
+	}
+
+	if report.count_gc(first_idx) > 0 {
+		print_gc(report, first_idx)
+	}
+
+	if setup.get_sampling_interval() > 0 {
+		print_goroutine_samples(report, first_idx)
+	}
+
+	if report.count_created_threads(first_idx) > 0 {
+		print_threads(report, first_idx)
+	}
+
+	if report.count_observations() > first_idx && report.get_last_observation().are_context_switches_read() {
+		print_context_switches(report, first_idx)
+	}
+
+	if setup.is_perf_counted() {
+		if report.count_observations() > first_idx && report.get_last_observation().is_perf_counted() {
+			print_perf_counts(report, first_idx)
+		} else {
+			print_perf_unavailable()
+		}
+	}
+
+	if setup.get_outlier_rule() != OR_None && !setup.is_streaming() {
+		print_trimmed(report, first_idx, setup.get_outlier_rule())
+	}
+
+	if setup.get_histogram_bounds() != nil && report.count_observations() > first_idx {
+		print_histogram(report.get_last_observation())
+	}
+
+	if points := report.collect_scale_points(first_idx); !setup.is_bounded() && len(points) > 2 {
+		print_scalability(points)
+	}
+
+	if report.is_normalized() {
+		print_normalized(report, first_idx)
+	}
+
+	if setup.get_ci_method() == CI_Bootstrap && !setup.is_streaming() {
+		print_bootstrap(report, first_idx)
+	} else if setup.count_reps() > 1 {
+		print_confidence(report, first_idx)
+	}
+
+	if setup.count_reps() > 1 {
+		print_jitter(report, first_idx)
+	}
+
+	if !setup.is_streaming() {
+		print_task_tables(report, first_idx, setup)
+	}
+
+	print_profit_duration(since(start))
+
+	return failure
+}
+
+// Rows are separated where the next task count needs another CPU's worth of tasks
+func is_next_cpu_block(n_tasks, next_n_tasks int) bool {
+	return (n_tasks-1)/count_cpus() != (next_n_tasks-1)/count_cpus()
+}
+
+// Tables derived from single tasks, which streamed observations do not keep
+func print_task_tables(report *Report, first_idx int, setup Setup) {
+
+	print_stage_times(report, first_idx)
+
+	// Tasks submitted to busy workers or slots wait before they run
+	switch setup.get_executor() {
+	case EX_OpenLoop, EX_Pool, EX_Semaphore:
+		print_queue_waits(report, first_idx)
+	}
+
+	if setup.get_executor() == EX_Chunked {
+		print_latencies(report, first_idx)
+	}
+
+	// Other executors hand tasks over to running goroutines,
+	// so their latency is mostly waiting in a queue
+	switch setup.get_executor() {
+	case EX_Batch, EX_Overlap, EX_Graph:
+		print_sched_latencies(report, first_idx)
+	}
+
+	switch setup.get_executor() {
+	case EX_Batch, EX_Process:
+		if !setup.is_bounded() {
+			print_series(report, first_idx)
+		}
+	}
+
+	if setup.get_executor() == EX_Graph {
+		print_graph_paths(report, first_idx)
+	}
+
+	if setup.get_launch_rate() > 0 {
+		print_rates(report, first_idx)
+	}
+
+	if setup.get_executor() == EX_OpenLoop || setup.get_launch_rate() > 0 {
+		print_littles_law(report, first_idx)
+	}
+
+	if setup.get_executor() == EX_RampUp && report.count_observations() > first_idx {
+		print_ramp_throughput(report.get_last_observation())
+	}
+}
+
+func measure_concurrency_profit(ctx context.Context, report *Report, task_counts []int, setups []Setup) error {
+
+	for _, setup := range setups {
+		if err := test_concurrency_profit(ctx, report, task_counts, setup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Runs the same task matrix with a goroutine per task, in series, and with a pool
+// of as many workers, so that the difference shows the cost of creating goroutines
+func compare_executors(ctx context.Context, report *Report, task_counts []int, setup Setup) error {
+
+	setup.set_executor(EX_Batch)
+	goroutines_idx := report.count_observations()
+
+	if err := test_concurrency_profit(ctx, report, task_counts, setup); err != nil {
+		return err
+	}
+
+	setup.set_executor(EX_Pool)
+	pool_idx := report.count_observations()
+
+	if err := test_concurrency_profit(ctx, report, task_counts, setup); err != nil {
+		return err
+	}
+
+	print_comparison_header()
+
+	for _, n_tasks := range task_counts {
+
+		goroutines_obs := report.find_observation(goroutines_idx, n_tasks)
+		pool_obs := report.find_observation(pool_idx, n_tasks)
+
+		if goroutines_obs == nil || pool_obs == nil {
+			break
+		}
+
+		print_comparison_entry(
+			n_tasks,
+			report.collect_repetitions(goroutines_obs).get_aggregate(metric_total_duration, setup.get_aggregate()),
+			report.collect_repetitions(pool_obs).get_aggregate(metric_total_duration, setup.get_aggregate()))
+	}
+
+	print_comparison_footer()
+
+	return nil
+}
+
+func measure_executor_overhead(ctx context.Context, report *Report, task_counts []int, setups []Setup) error {
+
+	for _, setup := range setups {
+		if err := compare_executors(ctx, report, task_counts, setup); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Generating shell completion
+
+const PROGRAM_NAME = "conctest"
+
+type Shell = int
+
+const (
+	SH_Bash = iota
+	SH_Zsh
+	SH_Fish
+)
+
+func parse_shell(s string) (Shell, bool) {
+	switch s {
+	case "bash":
+		return SH_Bash, true
+	case "zsh":
+		return SH_Zsh, true
+	case "fish":
+		return SH_Fish, true
+	default:
+		return SH_Bash, false
+	}
+}
+
+// Values completed after the options that take a name
+func get_completed_values() map[string][]string {
+	return map[string][]string{
+		"workload": WORKLOAD_NAMES,
+		"executor": EXECUTOR_NAMES,
+		"preset":   PRESET_NAMES,
+	}
+}
+
+func format_option_flags() []string {
+
+	flags := []string{}
+
+	for _, name := range KNOWN_OPTIONS {
+		flags = append(flags, "--"+name)
+	}
+
+	return flags
+}
+
+func format_completion(shell Shell) string {
+	switch shell {
+	case SH_Zsh:
+		return format_zsh_completion()
+	case SH_Fish:
+		return format_fish_completion()
+	default:
+		return format_bash_completion()
+	}
+}
+
+func format_bash_completion() string {
+
+	text := fmt.Sprintf("_%s() {\n", PROGRAM_NAME) +
+		"\tlocal cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n" +
+		"\tcase \"$prev\" in\n"
+
+	values := get_completed_values()
+
+	for _, name := range KNOWN_OPTIONS {
+		if option_values, ok := values[name]; ok {
+			text += fmt.Sprintf("\t--%s) COMPREPLY=($(compgen -W \"%s\" -- \"$cur\")); return;;\n", name, strings.Join(option_values, " "))
+		}
+	}
+
+	text += "\tcompletion) COMPREPLY=($(compgen -W \"bash zsh fish\" -- \"$cur\")); return;;\n" +
+		"\tesac\n" +
+		"\tif [ \"$COMP_CWORD\" -eq 1 ]; then\n" +
+		fmt.Sprintf("\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(COMMAND_NAMES, " ")) +
+		"\telse\n" +
+		fmt.Sprintf("\t\tCOMPREPLY=($(compgen -W \"%s\" -- \"$cur\"))\n", strings.Join(format_option_flags(), " ")) +
+		"\tfi\n" +
+		"}\n" +
+		fmt.Sprintf("complete -o default -F _%s %s\n", PROGRAM_NAME, PROGRAM_NAME)
+
+	return text
+}
+
+func format_zsh_completion() string {
+
+	text := fmt.Sprintf("#compdef %s\n\n_%s() {\n", PROGRAM_NAME, PROGRAM_NAME) +
+		"\tif (( CURRENT == 2 )); then\n" +
+		fmt.Sprintf("\t\tcompadd -- %s\n", strings.Join(COMMAND_NAMES, " ")) +
+		"\t\treturn\n" +
+		"\tfi\n" +
+		"\tcase ${words[CURRENT-1]} in\n"
+
+	values := get_completed_values()
+
+	for _, name := range KNOWN_OPTIONS {
+		if option_values, ok := values[name]; ok {
+			text += fmt.Sprintf("\t--%s) compadd -- %s; return;;\n", name, strings.Join(option_values, " "))
+		}
+	}
+
+	text += "\tcompletion) compadd -- bash zsh fish; return;;\n" +
+		"\tesac\n" +
+		fmt.Sprintf("\tcompadd -- %s\n", strings.Join(format_option_flags(), " ")) +
+		"\t_files\n" +
+		"}\n\n" +
+		fmt.Sprintf("compdef _%s %s\n", PROGRAM_NAME, PROGRAM_NAME)
+
+	return text
+}
+
+func format_fish_completion() string {
+
+	text := fmt.Sprintf("complete -c %s -n __fish_use_subcommand -f -a \"%s\"\n", PROGRAM_NAME, strings.Join(COMMAND_NAMES, " ")) +
+		fmt.Sprintf("complete -c %s -n \"__fish_seen_subcommand_from completion\" -f -a \"bash zsh fish\"\n", PROGRAM_NAME)
+
+	values := get_completed_values()
+
+	for _, name := range KNOWN_OPTIONS {
+		if option_values, ok := values[name]; ok {
+			text += fmt.Sprintf("complete -c %s -l %s -x -a \"%s\"\n", PROGRAM_NAME, name, strings.Join(option_values, " "))
+		} else {
+			text += fmt.Sprintf("complete -c %s -l %s\n", PROGRAM_NAME, name)
+		}
+	}
+
+	return text
+}
+
+// Accepting arguments
+
+func validate_usize(s string) bool {
+	r, _ := regexp.Compile(`^\d+$`)
+	return r.Match([]byte(s))
+}
+
+func parse_int(s string) int {
+	if validate_usize(s) {
+		i, _ := strconv.Atoi(s)
+		return int(i)
+	} else {
+		return 0
+	}
+}
+
+func parse_float(s string) float64 {
+	f, err := strconv.ParseFloat(s, 64)
+	if err == nil {
+		return f
+	} else {
+		return -1
+	}
+}
+
+func is_option(s string) bool {
+	return strings.HasPrefix(s, "--")
+}
+
+// Flags never take the next argument as their value, so that
+// --dry-run profit still names the command; --flag=false unsets one
+var FLAG_OPTIONS = []string{
+	"cold-warm", "cpu-time", "dry-run", "explain", "fail-fast", "fixed-start", "help", "no-color",
+	"normalize", "perf", "quiet", "resume", "streaming", "sys", "task-allocs",
+}
+
+func split_options(args []string) ([]string, map[string]string) {
+
+	positional := []string{}
+	options := map[string]string{}
+
+	for idx := 0; idx < len(args); idx++ {
+		if args[idx] == "-q" {
+			options["quiet"] = "true"
+		} else if args[idx] == "-h" {
+			options["help"] = "true"
+		} else if is_option(args[idx]) {
+			name, value, has_value := strings.Cut(strings.TrimPrefix(args[idx], "--"), "=")
+			if !has_value {
+				if idx+1 < len(args) && !is_option(args[idx+1]) && !slices.Contains(FLAG_OPTIONS, name) {
+					value = args[idx+1]
+					idx++
+				} else {
+					value = "true"
+				}
+			}
+			options[name] = value
+		} else {
+			positional = append(positional, args[idx])
+		}
+	}
+
+	return positional, options
+}
+
+// A config given as - comes from stdin, so that other tools can pipe
+// experiments in without temporary files
+const STDIN_PATH = "-"
+
+func read_config(path string) (string, error) {
+
+	var content []byte
+	var err error
+
+	if path == STDIN_PATH {
+		content, err = io.ReadAll(os.Stdin)
+	} else {
+		content, err = os.ReadFile(path)
+	}
+
+	return string(content), err
+}
+
+// Reads options from "name = value" lines in the manner of TOML:
+// comments start with #, strings may be quoted, arrays become lists
+// separated by commas, and false leaves a flag unset
+func parse_config(path, content string) (map[string]string, error) {
+
+	if path == STDIN_PATH {
+		path = "stdin"
+	}
+
+	options := map[string]string{}
+
+	for line_idx, line := range strings.Split(content, "\n") {
+
+		name, value, err := parse_config_line(line)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line_idx+1, err)
+		} else if name == "config" {
+			return nil, fmt.Errorf("%s:%d: unknown option %s", path, line_idx+1, name)
+		}
+
+		if name != "" && value != "false" {
+			options[name] = value
+		}
+	}
+
+	return options, nil
+}
+
+// A "name = value" line of a config file, no name for an empty line or a comment
+func parse_config_line(line string) (string, string, error) {
+
+	line = strings.TrimSpace(strip_config_comment(line))
+
+	if line == "" {
+		return "", "", nil
+	}
+
+	name, value, ok := strings.Cut(line, "=")
+	name = strings.Trim(strings.TrimSpace(name), "\"")
+	value = strings.TrimSpace(value)
+
+	if !ok || name == "" || value == "" {
+		return "", "", errors.New("expected name = value")
+	} else if !is_known_option(name) {
+		return "", "", fmt.Errorf("unknown option %s", name)
+	}
+
+	value, ok = parse_config_value(value)
+
+	if !ok {
+		return "", "", fmt.Errorf("malformed value of %s", name)
+	} else {
+		return name, value, nil
+	}
+}
+
+// A # inside a quoted string does not start a comment
+func strip_config_comment(line string) string {
+
+	quoted := false
+
+	for idx, char := range line {
+		if char == '"' {
+			quoted = !quoted
+		} else if char == '#' && !quoted {
+			return line[:idx]
+		}
+	}
+
+	return line
+}
+
+func parse_config_value(value string) (string, bool) {
+
+	if strings.HasPrefix(value, "[") {
+		if !strings.HasSuffix(value, "]") {
+			return "", false
+		}
+		items := []string{}
+		for _, item := range strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, "["), "]"), ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				item, ok := parse_config_value(item)
+				if !ok {
+					return "", false
+				}
+				items = append(items, item)
+			}
+		}
+		return strings.Join(items, ","), len(items) > 0
+	} else if strings.HasPrefix(value, "\"") {
+		unquoted, err := strconv.Unquote(value)
+		return unquoted, err == nil
+	} else {
+		return value, !strings.ContainsAny(value, " \t\"")
+	}
+}
+
+// Options of a whole batch, which its experiments share
+var BATCH_OPTIONS = []string{
+	"baseline", "batch", "checkpoint", "config", "cpuprofile", "deadline", "dry-run", "every", "explain",
+	"gomaxprocs", "label", "machine", "memprofile", "no-color", "normalize", "out", "out-dir", "preempt", "preset", "quiet", "resume",
+	"seed", "sys", "tag", "tolerance", "trace", "trace-tasks",
+}
+
+// Reads experiments from a file with a line of options like
+// "--workload fsync --tasks 8" per experiment, or with documents
+// in the config format separated by "---" lines
+func load_batch(path string) ([]map[string]string, error) {
+
+	content, err := os.ReadFile(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	experiments := []map[string]string{}
+	document := map[string]string{}
+
+	for line_idx, line := range strings.Split(string(content), "\n") {
+
+		line = strings.TrimSpace(strip_config_comment(line))
+
+		// A separator or a line of options closes the document before it
+		if (line == "---" || strings.HasPrefix(line, "--")) && len(document) > 0 {
+			experiments = append(experiments, document)
+			document = map[string]string{}
+		}
+
+		if line == "" || line == "---" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "--") {
+			positional, options := split_options(strings.Fields(line))
+
+			if len(positional) > 0 {
+				return nil, fmt.Errorf("%s:%d: unexpected argument %q", path, line_idx+1, positional[0])
+			}
+
+			names := []string{}
+
+			for name := range options {
+				names = append(names, name)
+			}
+
+			sort.Strings(names)
+
+			for _, name := range names {
+				if err := check_batch_option(name); err != nil {
+					return nil, fmt.Errorf("%s:%d: %w", path, line_idx+1, err)
+				}
+			}
+
+			experiments = append(experiments, options)
+		} else {
+			name, value, err := parse_config_line(line)
+
+			if err == nil {
+				err = check_batch_option(name)
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line_idx+1, err)
+			}
+
+			if value != "false" {
+				document[name] = value
+			}
+		}
+	}
+
+	if len(document) > 0 {
+		experiments = append(experiments, document)
+	}
+
+	if len(experiments) == 0 {
+		return nil, fmt.Errorf("%s: no experiments", path)
+	} else {
+		return experiments, nil
+	}
+}
+
+func check_batch_option(name string) error {
+	if !is_known_option(name) {
+		return fmt.Errorf("unknown option --%s", name)
+	} else if slices.Contains(BATCH_OPTIONS, name) {
+		return fmt.Errorf("--%s applies to the whole batch, give it on the command line", name)
+	} else {
+		return nil
+	}
+}
+
+const ENV_PREFIX = "CONCTEST_"
+
+// Reads options from variables like CONCTEST_TASK_MS=50 for --task-ms 50;
+// false leaves a flag unset, as in a config file
+func load_env_options(environ []string) (map[string]string, error) {
+
+	options := map[string]string{}
+
+	for _, variable := range environ {
+
+		key, value, _ := strings.Cut(variable, "=")
+
+		if !strings.HasPrefix(key, ENV_PREFIX) {
+			continue
+		}
+
+		name := strings.ReplaceAll(strings.ToLower(strings.TrimPrefix(key, ENV_PREFIX)), "_", "-")
+
+		if !is_known_option(name) {
+			return nil, fmt.Errorf("unknown option in the environment variable %s", key)
+		}
+
+		if value != "false" {
+			options[name] = value
+		}
+	}
+
+	return options, nil
+}
+
+// Options given on the command line take precedence over the environment,
+// and both over the config file
+func merge_options(options, config_options map[string]string) {
+	for name, value := range config_options {
+		if _, ok := options[name]; !ok {
+			options[name] = value
+		}
+	}
+}
+
+// Presets choose the scale of a measurement for those who do not know
+// what to pick yet; tasks grow with CPUs, cycles are calibrated to a duration
+type Preset = int
+
+const (
+	PR_Quick = iota
+	PR_Standard
+	PR_Thorough
+)
+
+var PRESET_NAMES = []string{"quick", "standard", "thorough"}
+
+func parse_preset(s string) (Preset, bool) {
+	switch s {
+	case "quick":
+		return PR_Quick, true
+	case "standard":
+		return PR_Standard, true
+	case "thorough":
+		return PR_Thorough, true
+	default:
+		return PR_Quick, false
+	}
+}
+
+func get_preset_options(preset Preset, n_cpus int) map[string]string {
+	switch preset {
+	case PR_Standard:
+		return map[string]string{
+			"tasks":   strconv.Itoa(min(max(4*n_cpus, 8), 64)),
+			"task-ms": "100",
+			"reps":    "3",
+			"warmup":  "1",
+		}
+	case PR_Thorough:
+		return map[string]string{
+			"tasks":     strconv.Itoa(min(max(8*n_cpus, 16), 128)),
+			"task-ms":   "300",
+			"reps":      "10",
+			"warmup":    "3",
+			"cold-warm": "true",
+		}
+	default:
+		return map[string]string{
+			"tasks":   strconv.Itoa(min(max(2*n_cpus, 4), 16)),
+			"task-ms": "20",
+			"reps":    "1",
+		}
+	}
+}
+
+// Exit codes let scripts tell outcomes apart without reading the output
+type ExitCode = int
+
+const (
+	EXIT_Success = iota
+	EXIT_Failure
+	EXIT_InvalidArgs
+	EXIT_IOError
+	EXIT_Regression
+	EXIT_Interrupted
+)
+
+// Files that cannot be read or written end the run with EXIT_IOError
+func exit_on_io_error(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(EXIT_IOError)
+	}
+}
+
+// An abort on a failed task outweighs the interruption it causes
+func get_measurement_exit_code(ctx context.Context, err error) ExitCode {
+	switch {
+	case err != nil:
+		return EXIT_Failure
+	case ctx.Err() != nil:
+		return EXIT_Interrupted
+	default:
+		return EXIT_Success
+	}
+}
+
+type Command = int
+
+const (
+	CMD_Help = iota
+	CMD_RequestSysParams
+	CMD_MeasureConcurrencyProfit
+	CMD_CompareExecutors
+	CMD_RunChildTask
+	CMD_CompareReports
+	CMD_ListReports
+	CMD_GenerateCompletion
+)
+
+// Commands offered by shell completion, without aliases and the hidden child task
+var COMMAND_NAMES = []string{"sys", "profit", "executors", "compare", "list", "completion", "help"}
+
+const ARG_IDX_COMMAND = 1
+
+// Every option the commands understand, anything else is a typo
+var KNOWN_OPTIONS = []string{
+	"aggregate", "alpha", "arrival-rate", "baseline", "batch", "bound-ms", "buffer", "burst",
+	"checkpoint", "chunk", "ci", "cold-warm", "config", "cpu-time", "cpuprofile", "cycles", "deadline", "dist", "dry-run",
+	"every", "executor", "explain", "fail-fast", "fixed-start", "fsync-dir", "gomaxprocs", "graph",
+	"help", "histogram", "keys", "label", "lock-thread", "machine", "max-cv", "memprofile", "no-color", "noise", "normalize",
+	"order", "out", "out-dir", "outliers", "perf", "preempt", "preset", "quiet", "ramp-ms", "rate", "reps", "resume",
+	"sample-ms", "seed", "series", "series-per-cpu", "spread", "stage-cycles", "stages",
+	"stagger", "streaming", "sys", "tag", "task-allocs", "task-ms", "task-timeout", "tasks",
+	"tight-len", "tolerance", "trace", "trace-tasks", "warmup", "width", "workload",
+	"write-ratio",
+}
+
+func is_known_option(name string) bool {
+	for _, known := range KNOWN_OPTIONS {
+		if name == known {
+			return true
+		}
+	}
+	return false
+}
+
+type Args struct {
+	command        Command
+	command_name   string
+	extra_args     []string
+	shell_name     string
+	options_err    error
+	task_counts    []int
+	cycle_counts   []int
+	series_sizes   []int
+	series_auto    bool
+	out_file_path  string
+	options        map[string]string
+	sizing         TaskSizing
+	sizing_valid   bool
+	task_ms        int
+	workload_kinds []WorkloadKind
+	workload_valid bool
+	deadline_sec   int
+	executors      []Executor
+	executor_valid bool
+	report_paths   []string
+	experiment_idx int
+	experiments    []Args
+	stdin_text     string
+}
+
+func (a Args) get_command() Command {
+	return a.command
+}
+
+func (a Args) get_shell() (Shell, bool) {
+	return parse_shell(a.shell_name)
+}
+
+func (a Args) get_report_paths() []string {
+	return a.report_paths
+}
+
+func (a Args) get_alpha() float64 {
+	return parse_float(a.get_option("alpha", "0.05"))
+}
+
+func (a Args) is_comparison_valid() bool {
+	return len(a.get_report_paths()) == 2 &&
+		a.get_alpha() > 0 &&
+		a.get_alpha() < 1
+}
+
+// Numbers of tasks of the observations, 1 to N for a single number N
+func (a Args) get_task_counts() []int {
+	return a.task_counts
+}
+
+// 0 when the task counts are malformed
+func (a Args) get_tasks_max() int {
+
+	tasks_max := 0
+
+	for _, n_tasks := range a.get_task_counts() {
+		tasks_max = max(tasks_max, n_tasks)
+	}
+
+	return tasks_max
+}
+
+func (a Args) get_cycle_counts() []int {
+	return a.cycle_counts
+}
+
+// The first of swept cycles, -1 when they are malformed
+func (a Args) get_n_cycles() int {
+	if len(a.get_cycle_counts()) == 0 {
+		return -1
+	} else {
+		return a.get_cycle_counts()[0]
+	}
+}
+
+func (a Args) get_series_sizes() []int {
+	return a.series_sizes
+}
+
+// The first of swept series sizes, 0 when they are malformed
+func (a Args) get_series_size() int {
+	if len(a.get_series_sizes()) == 0 {
+		return 0
+	} else {
+		return a.get_series_sizes()[0]
+	}
+}
+
+func (a Args) is_swept() bool {
+	return len(a.get_series_sizes()) > 1 || len(a.get_cycle_counts()) > 1
+}
+
+func (a Args) is_series_auto() bool {
+	return a.series_auto
+}
+
+// With --gomaxprocs, the value goes into the file name, so that
+// results of different runs do not get mixed up
+func (a Args) get_out_file_path() string {
+
+	if a.out_file_path == "" || !a.is_option_set("gomaxprocs") {
+		return a.out_file_path
+	}
+
+	ext := filepath.Ext(a.out_file_path)
+	base := strings.TrimSuffix(a.out_file_path, ext)
+
+	return fmt.Sprintf("%s-gomaxprocs%d%s", base, a.get_gomaxprocs(), ext)
+}
+
+// The seed of the random draws, a new one for every run unless given,
+// -1 when too large
+func (a Args) get_seed() int64 {
+
+	if !a.is_option_set("seed") {
+		return time.Now().UnixNano()
+	}
+
+	seed, err := strconv.ParseInt(a.get_option("seed", ""), 10, 64)
+
+	if err == nil {
+		return seed
+	} else {
+		return -1
+	}
+}
+
+// The label and the comma-separated key=value tags of --label and --tag,
+// which annotate a measurement and filter list and compare
+func (a Args) get_labels() RunLabels {
+
+	tags := []string{}
+
+	if a.is_option_set("tag") {
+		tags = strings.Split(a.get_option("tag", ""), ",")
+	}
+
+	return create_run_labels(a.get_option("label", ""), tags)
+}
+
+func (a Args) are_labels_valid() bool {
+	return is_label_valid(a.get_labels().get_label()) &&
+		!slices.ContainsFunc(a.get_labels().get_tags(), func(tag string) bool { return !is_tag_valid(tag) })
+}
+
+// Reports saved by --out-dir, or in the current directory, are listed and selected by labels
+func (a Args) get_reports_dir() string {
+	return a.get_option("out-dir", ".")
+}
+
+// Repeating the run on the interval given by --every, 0 for a single run
+func (a Args) get_interval() time.Duration {
+
+	if !a.is_option_set("every") {
+		return 0
+	}
+
+	interval, err := time.ParseDuration(a.get_option("every", ""))
+
+	if err != nil || interval < 0 {
+		return -1
+	} else {
+		return interval
+	}
+}
+
+// Runs the checks of sys before a measurement, into the same report
+func (a Args) is_sys_checked() bool {
+	return a.get_option("sys", "false") == "true"
+}
+
+// Saved by sys, referred to by measurements
+func (a Args) get_machine_path() string {
+	return a.get_option("machine", "")
+}
+
+func (a Args) get_out_dir() string {
+	return a.get_option("out-dir", "")
+}
+
+// The host and the parameters of the run, like myhost_tasks64_cycles10000000
+func (a Args) format_out_file_stem() string {
+
+	host, err := os.Hostname()
+
+	if err != nil {
+		host = "localhost"
+	}
+
+	if a.is_batched() {
+		batch_name := filepath.Base(a.get_batch_path())
+		return format_file_name_part(host) + "_" + format_file_name_part(strings.TrimSuffix(batch_name, filepath.Ext(batch_name)))
+	}
+
+	stem := fmt.Sprintf("%s_tasks%d", format_file_name_part(host), a.get_tasks_max())
+
+	if a.get_task_ms() > 0 {
+		return fmt.Sprintf("%s_ms%d", stem, a.get_task_ms())
+	} else if len(a.get_cycle_counts()) > 1 {
+		return fmt.Sprintf("%s_cycles%d-%d", stem, slices.Min(a.get_cycle_counts()), slices.Max(a.get_cycle_counts()))
+	} else {
+		return fmt.Sprintf("%s_cycles%d", stem, a.get_n_cycles())
+	}
+}
+
+// Letters, digits, dots, and dashes, anything else becomes a dash
+func format_file_name_part(s string) string {
+	return regexp.MustCompile(`[^A-Za-z0-9.-]`).ReplaceAllString(s, "-")
+}
+
+// A new file named by the stem and the moment, numbered when a file
+// of the same second exists already
+func name_out_file(dir, stem string, moment time.Time) string {
+
+	name := stem + "_" + moment.Format("20060102-150405")
+	path := filepath.Join(dir, name+".csv")
+
+	for n := 2; is_existing_file(path); n++ {
+		path = filepath.Join(dir, fmt.Sprintf("%s-%d.csv", name, n))
+	}
+
+	return path
+}
+
+func is_existing_file(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (a Args) get_gomaxprocs() int {
+	return parse_int(a.get_option("gomaxprocs", strconv.Itoa(count_cpus())))
+}
+
+// Options passed on to child processes, except those
+// that concern the measurement as a whole rather than a task
+func (a Args) format_child_options() []string {
+
+	child_options := []string{}
+
+	for name, value := range a.options {
+		switch name {
+		case "config", "batch", "preset", "checkpoint", "resume", "quiet", "no-color", "every", "explain", "label", "tag", "machine", "sys", "tasks", "cycles", "series", "out", "out-dir", "task-ms", "deadline", "executor", "reps", "warmup", "noise", "bound-ms", "trace", "trace-tasks", "cpuprofile", "memprofile":
+		default:
+			child_options = append(child_options, "--"+name+"="+value)
+		}
+	}
+
+	return child_options
+}
+
+func (a Args) is_option_set(name string) bool {
+	_, ok := a.options[name]
+	return ok
+}
+
+func (a Args) get_option(name, default_value string) string {
+	if value, ok := a.options[name]; ok {
+		return value
+	} else {
+		return default_value
+	}
+}
+
+func (a Args) get_sizing() TaskSizing {
+	return a.sizing
+}
+
+func (a Args) get_task_ms() int {
+	return a.task_ms
+}
+
+// Without cycles, they are calibrated to a duration long enough to dwarf
+// the overhead of the harness and short enough for a quick run
+const AUTO_TASK_MS = 200
+
+func (a Args) format_default_task_ms() string {
+	if a.is_option_set("cycles") {
+		return "0"
+	} else {
+		return strconv.Itoa(AUTO_TASK_MS)
+	}
+}
+
+func (a Args) get_deadline_sec() int {
+	return a.deadline_sec
+}
+
+func (a Args) get_executors() []Executor {
+	return a.executors
+}
+
+func (a Args) get_workload_params() WorkloadParams {
+	return create_workload_params(
+		a.get_option("fsync-dir", os.TempDir()),
+		parse_int(a.get_option("stages", "4")),
+		parse_int(a.get_option("buffer", "0")),
+		parse_int(a.get_option("stage-cycles", "1000")),
+		parse_int(a.get_option("keys", "1024")),
+		parse_float(a.get_option("write-ratio", "0.1")),
+		parse_int(a.get_option("tight-len", "1000000")))
+}
+
+func (a Args) get_workloads() []Workload {
+
+	workloads := []Workload{}
+
+	params := a.get_workload_params()
+
+	for _, kind := range a.workload_kinds {
+		workloads = append(workloads,
+			create_workload(kind, a.get_option("fixed-start", "false") == "true", params))
+	}
+
+	return workloads
+}
+
+func (a Args) get_arrival_rate() float64 {
+	return parse_float(a.get_option("arrival-rate", "10"))
+}
+
+func (a Args) count_reps() int {
+	return parse_int(a.get_option("reps", "1"))
+}
+
+func (a Args) get_aggregate() Aggregate {
+	aggregate, _ := parse_aggregate(a.get_option("aggregate", "mean"))
+	return aggregate
+}
+
+func (a Args) is_aggregate_valid() bool {
+	_, ok := parse_aggregate(a.get_option("aggregate", "mean"))
+	return ok
+}
+
+func (a Args) count_warmups() int {
+	return parse_int(a.get_option("warmup", "0"))
+}
+
+func parse_thread_locks(s string) ([]bool, bool) {
+	switch s {
+	case "off":
+		return []bool{false}, true
+	case "on", "true":
+		return []bool{true}, true
+	case "both":
+		return []bool{false, true}, true
+	default:
+		return []bool{}, false
+	}
+}
+
+func (a Args) get_thread_locks() []bool {
+	thread_locks, _ := parse_thread_locks(a.get_option("lock-thread", "off"))
+	return thread_locks
+}
+
+func (a Args) get_launch_order() LaunchOrder {
+	launch_order, _ := parse_launch_order(a.get_option("order", "forward"))
+	return launch_order
+}
+
+func (a Args) is_launch_order_valid() bool {
+	_, ok := parse_launch_order(a.get_option("order", "forward"))
+	return ok
+}
+
+func (a Args) get_stagger_ms() int {
+	return parse_int(a.get_option("stagger", "0"))
+}
+
+func (a Args) get_baseline_path() string {
+	return a.get_option("baseline", "")
+}
+
+func (a Args) get_tolerance() float64 {
+	return parse_float(a.get_option("tolerance", "10"))
+}
+
+func (a Args) get_max_cv() float64 {
+	return parse_float(a.get_option("max-cv", "0"))
+}
+
+func (a Args) get_ci_method() ConfidenceMethod {
+	ci_method, _ := parse_confidence_method(a.get_option("ci", "t"))
+	return ci_method
+}
+
+func (a Args) is_ci_method_valid() bool {
+	_, ok := parse_confidence_method(a.get_option("ci", "t"))
+	return ok
+}
+
+func (a Args) get_outlier_rule() OutlierRule {
+	outlier_rule, _ := parse_outlier_rule(a.get_option("outliers", "none"))
+	return outlier_rule
+}
+
+func (a Args) is_outlier_rule_valid() bool {
+	_, ok := parse_outlier_rule(a.get_option("outliers", "none"))
+	return ok
+}
+
+func (a Args) get_task_timeout_ms() int {
+	return parse_int(a.get_option("task-timeout", "0"))
+}
+
+func (a Args) is_preempt_off() bool {
+	return a.get_option("preempt", "on") == "off"
+}
+
+func (a Args) is_preempt_valid() bool {
+	return a.get_option("preempt", "on") == "on" || a.is_preempt_off()
+}
+
+func (a Args) get_histogram_bounds() []time.Duration {
+	bounds, _ := parse_histogram_bounds(a.get_option("histogram", "none"))
+	return bounds
+}
+
+func (a Args) is_histogram_valid() bool {
+	_, ok := parse_histogram_bounds(a.get_option("histogram", "none"))
+	return ok
+}
+
+func (a Args) get_chunk_sizes() []int {
+
+	chunk_sizes := []int{}
+
+	for _, item := range strings.Split(a.get_option("chunk", "1"), ",") {
+		chunk_sizes = append(chunk_sizes, parse_int(item))
+	}
+
+	return chunk_sizes
+}
+
+func (a Args) is_chunk_sizes_valid() bool {
+
+	for _, chunk_size := range a.get_chunk_sizes() {
+		if chunk_size <= 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (a Args) get_launch_rate() float64 {
+	return parse_float(a.get_option("rate", "0"))
+}
+
+func (a Args) get_launch_burst() int {
+	return parse_int(a.get_option("burst", "1"))
+}
+
+func (a Args) get_graph_shape() GraphShape {
+	graph_shape, _ := parse_graph_shape(a.get_option("graph", "fan"))
+	return graph_shape
+}
+
+func (a Args) is_graph_shape_valid() bool {
+	_, ok := parse_graph_shape(a.get_option("graph", "fan"))
+	return ok
+}
+
+// The series size of each setup when not given
+func (a Args) get_graph_width(series_size int) int {
+	return parse_int(a.get_option("width", strconv.Itoa(series_size)))
+}
+
+func (a Args) count_noise_goroutines() int {
+	return parse_int(a.get_option("noise", "0"))
+}
+
+func (a Args) are_allocs_counted() bool {
+	return a.get_option("task-allocs", "false") == "true"
+}
+
+func (a Args) get_sample_ms() int {
+	return parse_int(a.get_option("sample-ms", "0"))
+}
+
+func (a Args) get_bounded_ms() int {
+	return parse_int(a.get_option("bound-ms", "0"))
+}
+
+// The graph, ramp, and open executors launch on their own schedules,
+// which a rate limit would not pace
+func (a Args) is_rate_valid() bool {
+	return a.get_launch_rate() == 0 || !slices.ContainsFunc(a.get_executors(), func(executor Executor) bool {
+		return executor == EX_Graph || executor == EX_RampUp || executor == EX_OpenLoop
+	})
+}
+
+// Bounded tasks all repeat side by side as a single batch, so
+// neither another executor nor a series size would take effect
+func (a Args) is_bound_valid() bool {
+	if a.get_bounded_ms() == 0 {
+		return true
+	} else {
+		return a.get_command() == CMD_MeasureConcurrencyProfit &&
+			!slices.ContainsFunc(a.get_executors(), func(executor Executor) bool { return executor != EX_Batch }) &&
+			!a.is_option_set("series")
+	}
+}
+
+func (a Args) get_ramp_ms() int {
+	return parse_int(a.get_option("ramp-ms", "100"))
+}
+
+func (a Args) get_cpu_profile_path() string {
+	return a.get_option("cpuprofile", "")
+}
+
+func (a Args) get_mem_profile_path() string {
+	return a.get_option("memprofile", "")
+}
+
+// Next to the report by default, so that any saved run can be resumed;
+// in an output directory the name leaves out the date, which changes
+// from one attempt to the next
+func (a Args) get_checkpoint_path() string {
+	if a.get_out_dir() != "" {
+		return a.get_option("checkpoint", filepath.Join(a.get_out_dir(), a.format_out_file_stem()+".checkpoint"))
+	} else if a.get_out_file_path() == "" {
+		return a.get_option("checkpoint", "")
+	} else {
+		return a.get_option("checkpoint", a.get_out_file_path()+".checkpoint")
+	}
+}
+
+func (a Args) is_batched() bool {
+	return a.is_option_set("batch")
+}
+
+func (a Args) get_batch_path() string {
+	return a.get_option("batch", "")
+}
+
+// Experiments of a batch count from 1, a run without a batch has 0
+func (a Args) get_experiment_idx() int {
+	return a.experiment_idx
+}
+
+// The experiments of a batch, or the run itself as the only one
+func (a Args) get_experiments() []Args {
+	if a.is_batched() {
+		return a.experiments
+	} else {
+		return []Args{a}
+	}
+}
+
+func (a Args) is_resumed() bool {
+	return a.get_option("resume", "false") == "true"
+}
+
+// Options that shape the observations, which a resumed run must share
+// with the interrupted one
+func (a Args) format_fingerprint() string {
+
+	fields := []string{a.command_name}
+
+	for name, value := range a.options {
+		switch name {
+		case "config", "out", "out-dir", "checkpoint", "resume", "quiet", "no-color", "deadline", "dry-run", "every", "explain", "label", "tag", "machine", "sys", "trace", "trace-tasks", "cpuprofile", "memprofile":
+		default:
+			fields = append(fields, "--"+name+"="+value)
+		}
+	}
+
+	sort.Strings(fields[1:])
+
+	return strings.Join(fields, " ")
+}
+
+// Shows the plan and the estimated time instead of measuring
+func (a Args) is_dry_run() bool {
+	return a.get_option("dry-run", "false") == "true"
+}
+
+// Defines every metric with its formula and the baselines used
+func (a Args) is_explained() bool {
+	return a.get_option("explain", "false") == "true"
+}
+
+// Monochrome output even on a terminal, as NO_COLOR asks for
+func (a Args) is_color_off() bool {
+	return a.get_option("no-color", "false") == "true"
+}
+
+// No progress bar, also given as -q
+func (a Args) is_quiet() bool {
+	return a.get_option("quiet", "false") == "true"
+}
+
+func (a Args) get_trace_path() string {
+	return a.get_option("trace", "")
+}
+
+// 0 traces the whole measurement
+func (a Args) get_trace_tasks() int {
+	return parse_int(a.get_option("trace-tasks", "0"))
+}
+
+func (a Args) is_perf_counted() bool {
+	return a.get_option("perf", "false") == "true"
+}
+
+func (a Args) is_cpu_timed() bool {
+	return a.get_option("cpu-time", "false") == "true"
+}
+
+func (a Args) is_streaming() bool {
+	return a.get_option("streaming", "false") == "true"
+}
+
+func (a Args) is_normalized() bool {
+	return a.get_option("normalize", "false") == "true"
+}
+
+func (a Args) is_cold_warm() bool {
+	return a.get_option("cold-warm", "false") == "true"
+}
+
+func (a Args) is_fail_fast() bool {
+	return a.get_option("fail-fast", "false") == "true"
+}
+
+func (a Args) get_setups(cycle_counts []int) []Setup {
+	return a.make_setups(cycle_counts, a.get_executors())
+}
+
+// The cross product of workloads, executors, thread locking,
+// and swept series sizes and cycles
+func (a Args) make_setups(cycle_counts []int, executors []Executor) []Setup {
+
+	setups := []Setup{}
+
+	for _, workload := range a.get_workloads() {
+		for _, executor := range executors {
+			for _, thread_locked := range a.get_thread_locks() {
+				for _, series_size := range a.get_series_sizes() {
+					for _, n_cycles := range cycle_counts {
+						setups = append(setups, a.make_setup(n_cycles, series_size, workload, executor, thread_locked)...)
+					}
+				}
+			}
+		}
+	}
+
+	return setups
+}
+
+// More than one setup for the chunked executor, one per chunk size
+func (a Args) make_setup(n_cycles, series_size int, workload Workload, executor Executor, thread_locked bool) []Setup {
+
+	setup := create_setup(
+		n_cycles,
+		series_size,
+		a.get_sizing(),
+		workload,
+		executor,
+		a.is_fail_fast(),
+		a.get_arrival_rate(),
+		a.count_warmups(),
+		a.count_reps(),
+		a.get_aggregate())
+	setup.set_thread_locked(thread_locked)
+	setup.set_launching(a.get_launch_order(), from_ms(a.get_stagger_ms()))
+	setup.set_ramp_period(from_ms(a.get_ramp_ms()))
+	setup.set_bound(from_ms(a.get_bounded_ms()))
+	setup.set_n_noise(a.count_noise_goroutines())
+	setup.set_child_options(a.format_child_options())
+	setup.set_graph(a.get_graph_shape(), a.get_graph_width(series_size))
+	setup.set_launch_rate(a.get_launch_rate(), a.get_launch_burst())
+	setup.set_task_timeout(from_ms(a.get_task_timeout_ms()))
+	setup.set_outlier_rule(a.get_outlier_rule())
+	setup.set_ci_method(a.get_ci_method())
+	setup.set_max_cv(a.get_max_cv())
+	setup.set_cold_warm(a.is_cold_warm())
+	setup.set_histogram_bounds(a.get_histogram_bounds())
+	setup.set_streaming(a.is_streaming())
+	setup.set_cpu_timed(a.is_cpu_timed())
+	setup.set_perf_counted(a.is_perf_counted())
+	setup.set_sampling_interval(from_ms(a.get_sample_ms()))
+	setup.set_allocs_counted(a.are_allocs_counted())
+	setup.set_swept(a.is_swept())
+	setup.set_experiment_idx(a.get_experiment_idx())
+
+	setups := []Setup{}
+
+	if executor == EX_Chunked {
+		for _, chunk_size := range a.get_chunk_sizes() {
+			setup.set_chunk_size(chunk_size)
+			setups = append(setups, setup)
+		}
+	} else {
+		setups = append(setups, setup)
+	}
+
+	return setups
+}
+
+// The one-letter commands of the positional scheme remain as aliases
+func parse_command(name string) (Command, bool) {
+	switch name {
+	case "", "help":
+		return CMD_Help, true
+	case "sys", "s":
+		return CMD_RequestSysParams, true
+	case "profit", "p":
+		return CMD_MeasureConcurrencyProfit, true
+	case "executors", "c":
+		return CMD_CompareExecutors, true
+	case "t":
+		return CMD_RunChildTask, true
+	case "compare":
+		return CMD_CompareReports, true
+	case "list":
+		return CMD_ListReports, true
+	case "completion":
+		return CMD_GenerateCompletion, true
+	default:
+		return CMD_Help, false
+	}
+}
+
+// Accepts whole numbers also in the float notation like 1e7, -1 if invalid
+func parse_whole(s string) int {
+
+	value := parse_float(s)
+
+	if value < 0 || value != math.Trunc(value) || value > math.MaxInt32 {
+		return -1
+	} else {
+		return int(value)
+	}
+}
+
+// Values of a swept parameter: a number, a range like 1..64 or 1..64:step=4,
+// or a list of numbers and ranges separated by commas; nil if malformed
+func parse_sweep(s string) []int {
+
+	values := []int{}
+
+	for _, item := range strings.Split(s, ",") {
+
+		item_values := parse_sweep_item(strings.TrimSpace(item))
+
+		if item_values == nil {
+			return nil
+		}
+
+		values = append(values, item_values...)
+	}
+
+	return values
+}
+
+func parse_sweep_item(item string) []int {
+
+	bounds, step_text, has_step := strings.Cut(item, ":step=")
+	from_text, to_text, is_range := strings.Cut(bounds, "..")
+
+	if !is_range {
+		if value := parse_whole(bounds); value < 0 || has_step {
+			return nil
+		} else {
+			return []int{value}
+		}
+	}
+
+	from, to, step := parse_whole(from_text), parse_whole(to_text), 1
+
+	if has_step {
+		step = parse_whole(step_text)
+	}
+
+	if from < 0 || to < from || step <= 0 {
+		return nil
+	}
+
+	values := []int{}
+
+	for value := from; value <= to; value += step {
+		values = append(values, value)
+	}
+
+	return values
+}
+
+// A single number N keeps its meaning of observations of 1 to N tasks
+func (a Args) parse_task_counts() []int {
+
+	tasks := a.get_option("tasks", "0")
+	task_counts := parse_sweep(tasks)
+
+	if len(task_counts) == 1 && !strings.ContainsAny(tasks, ".,") {
+		task_counts = []int{}
+		for n_tasks := 1; n_tasks <= parse_whole(tasks); n_tasks++ {
+			task_counts = append(task_counts, n_tasks)
+		}
+	}
+
+	return task_counts
+}
+
+func (a Args) parse_series_sizes() ([]int, bool) {
+	if series := a.get_option("series", "auto"); series == "auto" {
+		return nil, true
+	} else {
+		return parse_sweep(series), false
+	}
+}
+
+func are_all_positive(values []int) bool {
+
+	for _, value := range values {
+		if value <= 0 {
+			return false
+		}
+	}
+
+	return len(values) > 0
+}
+
+func (a Args) calc_auto_series_size() int {
+
+	series_size := count_cpus() * parse_int(a.get_option("series-per-cpu", "1"))
+
+	if series_size > a.get_tasks_max() {
+		return a.get_tasks_max()
+	} else {
+		return series_size
+	}
+}
+
+func (a *Args) parse_sizing() {
+
+	distribution, ok := parse_distribution(a.get_option("dist", "fixed"))
+	spread := parse_float(a.get_option("spread", "0.5"))
+
+	a.sizing = create_task_sizing(distribution, spread)
+	a.sizing_valid = ok && spread >= 0 && spread <= 1
+}
+
+func (a *Args) parse(args []string) {
+
+	args, a.options = split_options(args)
+
+	// The environment may name the config file as well
+	env_options, err := load_env_options(os.Environ())
+	if err == nil {
+		merge_options(a.options, env_options)
+	} else {
+		a.options_err = err
+	}
+
+	a.resolve(args)
+}
+
+// Completes the options given with the config, the preset, and the batch,
+// and takes the command and its operands from the positional arguments
+func (a *Args) resolve(args []string) {
+
+	if a.is_option_set("config") && a.options_err == nil {
+		config_options, err := a.load_config()
+		if err == nil {
+			merge_options(a.options, config_options)
+		} else {
+			a.options_err = err
+		}
+	}
+
+	// A preset fills in only what is not given otherwise,
+	// and given cycles leave nothing to calibrate
+	if a.is_option_set("preset") {
+		if preset, ok := parse_preset(a.get_option("preset", "")); ok {
+			preset_options := get_preset_options(preset, count_cpus())
+			if a.is_option_set("cycles") {
+				delete(preset_options, "task-ms")
+			}
+			merge_options(a.options, preset_options)
+		} else if a.options_err == nil {
+			a.options_err = fmt.Errorf("unknown preset %q, expected quick, standard, or thorough", a.get_option("preset", ""))
+		}
+	}
+
+	if len(args) > ARG_IDX_COMMAND {
+		a.command_name = args[ARG_IDX_COMMAND]
+		if a.command_name == "compare" {
+			a.report_paths = args[ARG_IDX_COMMAND+1:]
+		} else if a.command_name == "completion" && len(args) > ARG_IDX_COMMAND+1 {
+			a.shell_name = args[ARG_IDX_COMMAND+1]
+			a.extra_args = args[ARG_IDX_COMMAND+2:]
+		} else {
+			a.extra_args = args[ARG_IDX_COMMAND+1:]
+		}
+	}
+
+	a.command, _ = parse_command(a.command_name)
+
+	// Any command given --help or -h displays the help instead of running
+	if a.is_option_set("help") {
+		a.command = CMD_Help
+	}
+
+	a.parse_values()
+
+	if a.is_batched() && a.options_err == nil {
+		experiments_options, err := load_batch(a.get_batch_path())
+		if err == nil {
+			for idx, options := range experiments_options {
+				a.experiments = append(a.experiments, a.create_experiment(idx+1, options))
+			}
+		} else {
+			a.options_err = err
+		}
+	}
+
+	if a.get_out_dir() != "" {
+		a.rename_out_file(time.Now())
+	}
+}
+
+// Arguments of a command run by another program, which passes the options
+// itself rather than through the command line and the environment
+func create_args(command_name string, options map[string]string) Args {
+
+	var args Args
+
+	args.options = map[string]string{}
+	merge_options(args.options, options)
+	args.resolve([]string{PROGRAM_NAME, command_name})
+
+	return args
+}
+
+// Every run into an output directory gets a file of its own
+func (a *Args) rename_out_file(moment time.Time) {
+	a.out_file_path = name_out_file(a.get_out_dir(), a.format_out_file_stem(), moment)
+}
+
+// Keeps a config read from stdin for a restarted copy of the process
+func (a *Args) load_config() (map[string]string, error) {
+
+	content, err := read_config(a.get_config_path())
+
+	if err != nil {
+		return nil, err
+	}
+
+	if a.get_config_path() == STDIN_PATH {
+		a.stdin_text = content
+	}
+
+	return parse_config(a.get_config_path(), content)
+}
+
+func (a Args) get_config_path() string {
+	return a.get_option("config", "")
+}
+
+// What a restarted copy of the process reads, stdin itself unless
+// the config has consumed it already
+func (a Args) get_stdin() io.Reader {
+	if a.get_config_path() == STDIN_PATH {
+		return strings.NewReader(a.stdin_text)
+	} else {
+		return os.Stdin
+	}
+}
+
+// Values of the options that take parsing beforehand
+func (a *Args) parse_values() {
+
+	a.task_counts = a.parse_task_counts()
+	a.cycle_counts = parse_sweep(a.get_option("cycles", "0"))
+	a.series_sizes, a.series_auto = a.parse_series_sizes()
+	a.out_file_path = a.get_option("out", "")
+
+	if a.series_auto {
+		a.series_sizes = []int{a.calc_auto_series_size()}
+	}
+
+	a.parse_sizing()
+	a.task_ms = parse_int(a.get_option("task-ms", a.format_default_task_ms()))
+	a.deadline_sec = parse_int(a.get_option("deadline", "0"))
+	a.executors, a.executor_valid = parse_executors(a.get_option("executor", "batch"))
+	a.workload_kinds, a.workload_valid = parse_workload_kinds(a.get_option("workload", "float"))
+}
+
+// Options of an experiment in a batch override those of the batch,
+// and its own cycles leave nothing to calibrate
+func (a Args) create_experiment(experiment_idx int, options map[string]string) Args {
+
+	experiment := a
+	experiment.options = map[string]string{}
+	experiment.experiment_idx = experiment_idx
+	experiment.experiments = nil
+
+	for name, value := range a.options {
+		experiment.options[name] = value
+	}
+
+	delete(experiment.options, "batch")
+
+	_, own_cycles := options["cycles"]
+	_, own_task_ms := options["task-ms"]
+
+	if own_cycles && !own_task_ms {
+		delete(experiment.options, "task-ms")
+	}
+
+	for name, value := range options {
+		experiment.options[name] = value
+	}
+
+	experiment.parse_values()
+
+	return experiment
+}
+
+type ArgCheck struct {
+	ok      bool
+	problem string
+}
+
+// Checks common to all commands: the command itself, stray arguments, and misspelled options
+func (a Args) validate_command() error {
+
+	if a.options_err != nil {
+		return a.options_err
+	}
+
+	if _, ok := parse_command(a.command_name); !ok {
+		return fmt.Errorf("unknown command %q, expected sys, profit, executors, compare, list, or completion", a.command_name)
+	}
+
+	if len(a.extra_args) > 0 {
+		return fmt.Errorf("unexpected argument %q, the command takes its values as options like --tasks 32", a.extra_args[0])
+	}
+
+	names := []string{}
+
+	for name := range a.options {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !is_known_option(name) {
+			return fmt.Errorf("unknown option --%s", name)
+		}
+	}
+
+	return nil
+}
+
+func (a Args) validate_completion() error {
+
+	if err := a.validate_command(); err != nil {
+		return err
+	}
+
+	if _, ok := a.get_shell(); !ok {
+		return fmt.Errorf("completion takes bash, zsh, or fish, got %q", a.shell_name)
+	} else {
+		return nil
+	}
+}
+
+func (a Args) validate_comparison() error {
+
+	if err := a.validate_command(); err != nil {
+		return err
+	}
+
+	if len(a.get_report_paths()) != 2 {
+		return fmt.Errorf("compare takes two reports, got %d", len(a.get_report_paths()))
+	} else if !a.is_comparison_valid() {
+		return fmt.Errorf("--alpha must lie strictly between 0 and 1, got %q", a.get_option("alpha", ""))
+	} else if a.is_option_set("label") {
+		return fmt.Errorf("compare takes labels as operands like label:before-fix, got --label")
+	} else if !a.are_labels_valid() {
+		return fmt.Errorf("--tag must be key=value pairs separated by commas%s", a.format_given("tag"))
+	} else {
+		return nil
+	}
+}
+
+func (a Args) validate_listing() error {
+
+	if err := a.validate_command(); err != nil {
+		return err
+	}
+
+	if !is_label_valid(a.get_labels().get_label()) {
+		return fmt.Errorf("--label must not contain commas%s", a.format_given("label"))
+	} else if !a.are_labels_valid() {
+		return fmt.Errorf("--tag must be key=value pairs separated by commas%s", a.format_given("tag"))
+	} else {
+		return nil
+	}
+}
+
+// Returns the first problem with the arguments of a measurement, nil if there is none
+func (a Args) validate() error {
+
+	if err := a.validate_command(); err != nil {
+		return err
+	}
+
+	if a.is_option_set("out") && a.get_out_dir() != "" {
+		return errors.New("give either --out or --out-dir")
+	}
+
+	if a.is_batched() {
+		return a.validate_batch()
+	}
+
+	if err := a.validate_numbers(); err != nil {
+		return err
+	}
+
+	series_max := slices.Max(append(a.get_series_sizes(), 0))
+	params := a.get_workload_params()
+
+	checks := []ArgCheck{
+		{a.is_option_set("tasks"), "--tasks is required"},
+		{are_all_positive(a.get_task_counts()), "--tasks must be a positive whole number, a range like 1..64:step=4, or a list" + a.format_given("tasks")},
+		{a.get_cycle_counts() != nil, "--cycles must be a whole non-negative number like 1e7, a range, or a list" + a.format_given("cycles")},
+		{are_all_positive(a.get_cycle_counts()) || a.get_task_ms() > 0, "--cycles must be positive unless --task-ms is given" + a.format_given("cycles")},
+		{are_all_positive(a.get_series_sizes()), "--series must be a positive whole number, a range, a list, or auto" + a.format_given("series")},
+		{series_max <= a.get_tasks_max(), fmt.Sprintf("--series must not exceed --tasks, a series of %d tasks needs at least as many, got --tasks up to %d", series_max, a.get_tasks_max())},
+		{a.sizing_valid, fmt.Sprintf("--dist must be fixed, uniform, or exponential, --spread within 0..1, got --dist %q --spread %q", a.get_option("dist", "fixed"), a.get_option("spread", "0.5"))},
+		{a.workload_valid, "--workload must name workloads among " + strings.Join(WORKLOAD_NAMES, ", ") + a.format_given("workload")},
+		{a.executor_valid, "--executor must name executors among " + strings.Join(EXECUTOR_NAMES, ", ") + a.format_given("executor")},
+		{a.get_arrival_rate() > 0, "--arrival-rate must be positive" + a.format_given("arrival-rate")},
+		{a.count_reps() > 0, "--reps must be a positive whole number" + a.format_given("reps")},
+		{len(a.get_thread_locks()) > 0, "--lock-thread must be off, on, or both" + a.format_given("lock-thread")},
+		{a.is_launch_order_valid(), "--order must be forward, reverse, or shuffle" + a.format_given("order")},
+		{a.get_ramp_ms() > 0, "--ramp-ms must be a positive whole number" + a.format_given("ramp-ms")},
+		{a.get_gomaxprocs() > 0, "--gomaxprocs must be a positive whole number" + a.format_given("gomaxprocs")},
+		{a.get_launch_rate() >= 0, "--rate must not be negative" + a.format_given("rate")},
+		{a.is_rate_valid(), "--rate cannot pace the graph, ramp, and open executors, which launch on schedules of their own"},
+		{a.get_launch_burst() > 0, "--burst must be a positive whole number" + a.format_given("burst")},
+		{a.is_graph_shape_valid(), "--graph must be fan or layers" + a.format_given("graph")},
+		{a.is_chunk_sizes_valid(), "--chunk must be positive whole numbers separated by commas" + a.format_given("chunk")},
+		{a.is_histogram_valid(), "--histogram must be none, log, or increasing bounds in ms" + a.format_given("histogram")},
+		{a.is_preempt_valid(), "--preempt must be on or off" + a.format_given("preempt")},
+		{a.is_outlier_rule_valid(), "--outliers must be none, iqr, or mad" + a.format_given("outliers")},
+		{a.is_ci_method_valid(), "--ci must be t or bootstrap" + a.format_given("ci")},
+		{a.get_max_cv() >= 0, "--max-cv must not be negative" + a.format_given("max-cv")},
+		{a.get_tolerance() >= 0, "--tolerance must not be negative" + a.format_given("tolerance")},
+		{a.get_graph_width(a.get_series_size()) > 0, "--width must be a positive whole number" + a.format_given("width")},
+		{a.is_aggregate_valid(), "--aggregate must be mean or median" + a.format_given("aggregate")},
+		{a.get_interval() >= 0 && (a.get_interval() > 0 || !a.is_option_set("every")), "--every must be a positive duration like 10m or 1h" + a.format_given("every")},
+		{a.get_seed() >= 0, "--seed must fit in 63 bits" + a.format_given("seed")},
+		{a.is_bound_valid(), "--bound-ms runs all tasks as a single batch, so it takes profit with the batch executor and no --series"},
+		{!a.is_option_set("out") || is_dir_existing(filepath.Dir(a.get_option("out", ""))) && !is_dir_existing(a.get_option("out", "")), "--out must name a file in an existing directory" + a.format_given("out")},
+		{is_label_valid(a.get_labels().get_label()), "--label must not contain commas" + a.format_given("label")},
+		{a.are_labels_valid(), "--tag must be key=value pairs separated by commas" + a.format_given("tag")},
+		{!a.is_resumed() || a.get_checkpoint_path() != "", "--resume needs --checkpoint or --out to find the checkpoint"},
+		{params.get_n_keys() > 0, "--keys must be a positive whole number" + a.format_given("keys")},
+		{params.get_tight_len() > 0, "--tight-len must be a positive whole number" + a.format_given("tight-len")},
+		{params.get_write_ratio() >= 0 && params.get_write_ratio() <= 1, "--write-ratio must lie within 0..1" + a.format_given("write-ratio")},
+	}
+
+	for _, check := range checks {
+		if !check.ok {
+			return errors.New(check.problem)
+		}
+	}
+
+	return nil
+}
+
+// Options read as whole numbers or as numbers, which would read as 0 or -1
+// when malformed and fail a later check with a misleading message
+var WHOLE_NUMBER_OPTIONS = []string{
+	"bound-ms", "buffer", "burst", "deadline", "gomaxprocs", "keys", "noise", "ramp-ms", "reps",
+	"sample-ms", "seed", "series-per-cpu", "stage-cycles", "stages", "stagger", "task-ms", "task-timeout",
+	"tight-len", "trace-tasks", "warmup", "width",
+}
+
+var NUMBER_OPTIONS = []string{"alpha", "arrival-rate", "max-cv", "rate", "spread", "tolerance", "write-ratio"}
+
+func (a Args) validate_numbers() error {
+
+	for _, name := range WHOLE_NUMBER_OPTIONS {
+		if a.is_option_set(name) && !validate_usize(a.get_option(name, "")) {
+			return fmt.Errorf("--%s must be a whole number%s", name, a.format_given(name))
+		}
+	}
+
+	for _, name := range NUMBER_OPTIONS {
+		if _, err := strconv.ParseFloat(a.get_option(name, "0"), 64); err != nil {
+			return fmt.Errorf("--%s must be a number%s", name, a.format_given(name))
+		}
+	}
+
+	return nil
+}
+
+// The value given to an option, for error messages
+func (a Args) format_given(name string) string {
+	return fmt.Sprintf(", got %q", a.get_option(name, ""))
+}
+
+// Every experiment of a batch has to be valid before any of them runs
+func (a Args) validate_batch() error {
+
+	for _, experiment := range a.get_experiments() {
+		if err := experiment.validate(); err != nil {
+			return fmt.Errorf("%s: experiment %d: %w", a.get_batch_path(), experiment.get_experiment_idx(), err)
+		}
+	}
+
+	return nil
+}
+
+// Reporting observations
+
+// An output of a measurement: the console, the report file, or whatever
+// a program measuring with Measure plugs in. OnTaskDone comes from
+// the goroutines of the tasks, at the same time for tasks running together
+type Reporter interface {
+	OnObservationStart(setup Setup, n_tasks int)
+	OnTaskDone(setup Setup, task Task)
+	OnObservationDone(setup Setup, report *Report, obs *Observation)
+	Flush(report *Report) error
+}
+
+// Every reporter of a measurement hears of every event
+type Reporters []Reporter
+
+func (r Reporters) OnObservationStart(setup Setup, n_tasks int) {
+	for _, reporter := range r {
+		reporter.OnObservationStart(setup, n_tasks)
+	}
+}
+
+func (r Reporters) OnTaskDone(setup Setup, task Task) {
+	for _, reporter := range r {
+		reporter.OnTaskDone(setup, task)
+	}
+}
+
+func (r Reporters) OnObservationDone(setup Setup, report *Report, obs *Observation) {
+	for _, reporter := range r {
+		reporter.OnObservationDone(setup, report, obs)
+	}
+}
+
+func (r Reporters) Flush(report *Report) error {
+
+	errs := []error{}
+
+	for _, reporter := range r {
+		errs = append(errs, reporter.Flush(report))
+	}
+
+	return errors.Join(errs...)
+}
+
+// Prints a row of the profit table once all repetitions
+// of a number of tasks are done
+type ConsoleReporter struct {
+	explained bool
+}
+
+func create_console_reporter(explained bool) *ConsoleReporter {
+	return &ConsoleReporter{explained}
+}
+
+func (c *ConsoleReporter) OnObservationStart(setup Setup, n_tasks int) {}
+
+func (c *ConsoleReporter) OnTaskDone(setup Setup, task Task) {}
+
+func (c *ConsoleReporter) OnObservationDone(setup Setup, report *Report, obs *Observation) {
+
+	if obs.get_rep_idx()+1 < setup.count_reps() {
+		return
+	}
+
+	setup.get_progress().clear()
+
+	if setup.is_bounded() {
+		print_bounded_entry(report.collect_repetitions(obs), setup.get_aggregate())
+	} else if setup.count_reps() > 1 {
+		print_repetitions_entry(report.collect_repetitions(obs), setup.get_aggregate())
+	} else {
+		print_profit_entry(obs)
+	}
+}
+
+// Explains the metrics at the end, with --explain
+func (c *ConsoleReporter) Flush(report *Report) error {
+
+	if c.explained {
+		print_explanation(report)
+	}
+
+	return nil
+}
+
+// Writes the report to a file; rounds after the first go to the end
+// of the file of the first one
+type CSVReporter struct {
+	path      string
+	appending bool
+	explained bool
+}
+
+func create_csv_reporter(path string, appending, explained bool) *CSVReporter {
+	return &CSVReporter{path, appending, explained}
+}
+
+func (c *CSVReporter) OnObservationStart(setup Setup, n_tasks int) {}
+
+func (c *CSVReporter) OnTaskDone(setup Setup, task Task) {}
+
+func (c *CSVReporter) OnObservationDone(setup Setup, report *Report, obs *Observation) {}
+
+func (c *CSVReporter) Flush(report *Report) error {
+
+	var err error
+
+	if c.appending {
+		err = append_file(c.path, func(w io.Writer) error {
+			io.WriteString(w, "\n")
+			return write_report(w, report, c.explained)
+		})
+	} else {
+		err = save_file(c.path, func(w io.Writer) error {
+			return write_report(w, report, c.explained)
+		})
+	}
+
+	// A report of a long run is not lost to a file that cannot be written
+	if err != nil {
+		print_unsaved_report(c.path, report, c.explained)
+	}
+
+	return err
+}
+
+// The console always, the report file with --out or --out-dir
+func (a Args) make_reporters(round int) Reporters {
+
+	reporters := Reporters{create_console_reporter(a.is_explained())}
+
+	if a.get_out_file_path() != "" {
+		appending := round > 1 && a.get_out_dir() == ""
+		reporters = append(reporters, create_csv_reporter(a.get_out_file_path(), appending, a.is_explained()))
+	}
+
+	return reporters
+}
+
+// Doing the job
+
+func attach_reporters(setups []Setup, reporters Reporters) []Setup {
+
+	for idx := range setups {
+		setups[idx].set_reporters(reporters)
+	}
+
+	return setups
+}
+
+func attach_tracer(setups []Setup, tracer *Tracer) []Setup {
+
+	for idx := range setups {
+		setups[idx].set_tracer(tracer)
+	}
+
+	return setups
+}
+
+func attach_checkpoint(setups []Setup, checkpoint *Checkpoint) []Setup {
+
+	for idx := range setups {
+		setups[idx].set_checkpoint(checkpoint)
+	}
+
+	return setups
+}
+
+// Why the measurement stopped before all observations were made, if it did
+func describe_interruption(ctx context.Context, err error) string {
+	switch {
+	case err != nil:
+		return "aborted on a failed task"
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return "deadline exceeded"
+	case ctx.Err() != nil:
+		return "interrupted by a signal"
+	default:
+		return ""
+	}
+}
+
+// The progress counts the planned work of n_runs runs of every setup
+func attach_progress(setups []Setup, task_counts []int, n_runs int, quiet bool) []Setup {
+
+	progress := open_progress(n_runs*count_planned_work(task_counts, setups), quiet)
+
+	for idx := range setups {
+		setups[idx].set_progress(progress)
+	}
+
+	return setups
+}
+
+// GODEBUG settings take effect only at the start of a process, so the measurement
+// runs in a copy of the process; returns the exit code of the copy
+func restart_with_godebug(setting string, stdin io.Reader) int {
+
+	self, err := os.Executable()
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "cannot restart with GODEBUG="+setting+":", err)
+		return EXIT_Failure
+	}
+
+	godebug := setting
+
+	if os.Getenv("GODEBUG") != "" {
+		godebug = os.Getenv("GODEBUG") + "," + setting
+	}
+
+	cmd := exec.Command(self, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), "GODEBUG="+godebug)
+	cmd.Stdin = stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		if exit_err, ok := err.(*exec.ExitError); ok {
+			return exit_err.ExitCode()
+		} else {
+			fmt.Fprintln(os.Stderr, "cannot restart with GODEBUG="+setting+":", err)
+			return EXIT_Failure
+		}
+	}
+
+	return 0
+}
+
+// Prints the plan of an experiment without running it
+func plan_experiment(args Args, n_experiments int, cycles_per_sec int) []PlanEntry {
+
+	if args.get_experiment_idx() > 0 {
+		print_experiment_title(args.get_experiment_idx(), n_experiments)
+	}
+
+	if args.is_series_auto() {
+		print_auto_series_size(args.get_series_size(), count_cpus())
+	}
+
+	cycle_counts := args.get_cycle_counts()
+
+	if args.get_task_ms() > 0 {
+		cycle_counts = []int{max(int(float64(cycles_per_sec)*from_ms(args.get_task_ms()).Seconds()), 1)}
+	}
+
+	setups := args.get_setups(cycle_counts)
+
+	if args.get_command() == CMD_CompareExecutors {
+		setups = pair_executors(args.make_setups(cycle_counts, []Executor{EX_Batch}))
+	}
+
+	print_dry_run_header(len(args.get_task_counts()), args.get_tasks_max(), cycles_per_sec)
+
+	entries := plan_measurement(setups, args.get_task_counts(), args.get_gomaxprocs(), cycles_per_sec)
+
+	for _, entry := range entries {
+		print_dry_run_entry(entry)
+	}
+
+	return entries
+}
+
+// Measures an experiment into the report, which collects the experiments of a batch
+func run_experiment(ctx context.Context, report *Report, args Args, n_experiments int, tracer *Tracer, checkpoint *Checkpoint, reporters Reporters) error {
+
+	if args.get_experiment_idx() > 0 {
+		print_experiment_title(args.get_experiment_idx(), n_experiments)
+	}
+
+	if args.is_series_auto() {
+		print_auto_series_size(args.get_series_size(), count_cpus())
+	}
+
+	cycle_counts := args.get_cycle_counts()
+
+	// Experiments of a batch name their cycles in the totals instead
+	if args.get_task_ms() > 0 {
+		n_cycles := calibrate_n_cycles(ctx, from_ms(args.get_task_ms()))
+		if ctx.Err() != nil {
+			return nil
+		}
+		print_calibrated_cycles(args.get_task_ms(), n_cycles)
+		cycle_counts = []int{n_cycles}
+		if args.get_experiment_idx() == 0 {
+			report.set_calibration(n_cycles, from_ms(args.get_task_ms()))
+		}
+	}
+
+	if args.get_command() == CMD_CompareExecutors {
+		// Comparing executors runs every setup twice, with goroutines and with a pool
+		setups := attach_progress(args.make_setups(cycle_counts, []Executor{EX_Batch}), args.get_task_counts(), 2, args.is_quiet())
+		return measure_executor_overhead(ctx, report, args.get_task_counts(), attach_reporters(attach_checkpoint(attach_tracer(setups, tracer), checkpoint), reporters))
+	} else {
+		setups := attach_progress(args.get_setups(cycle_counts), args.get_task_counts(), 1, args.is_quiet())
+		return measure_concurrency_profit(ctx, report, args.get_task_counts(), attach_reporters(attach_checkpoint(attach_tracer(setups, tracer), checkpoint), reporters))
+	}
+}
+
+// A report with the overheads and the seed of the measurement about to run
+func start_report(ctx context.Context, args Args, machine_id string) Report {
+
+	report := create_report(args.get_gomaxprocs())
+	report.set_machine_id(machine_id)
+	report.set_labels(args.get_labels())
+
+	// Every round draws anew unless the seed is given
+	RANDOM.reseed(args.get_seed())
+	report.set_seed(RANDOM.get_seed())
+	print_seed(report.get_seed())
+
+	// The system parameters, when checked, take the place of
+	// the calibrations of the measurement
+	if args.is_sys_checked() {
+		report.set_machine_profile(check_sysparams(ctx))
+		report.set_overheads(report.get_machine_profile().get_clock_cost(), report.get_machine_profile().get_launch_cost())
+		fmt.Fprintln(CONSOLE)
+	} else {
+		report.set_overheads(measure_clock_cost(), measure_launch_cost())
+	}
+
+	print_overheads(&report)
+
+	if args.is_normalized() && args.is_sys_checked() {
+		report.set_cycles_per_sec(report.get_machine_profile().get_cycles_per_sec())
+	} else if args.is_normalized() {
+		report.set_cycles_per_sec(count_cycles_per_sec(ctx))
+		print_machine_speed(report.get_cycles_per_sec())
+	}
+
+	return report
+}
+
+// Runs the experiments once into a report and saves it; with --every,
+// each round is a report of its own, appended to the file of --out
+func measure_round(ctx context.Context, args Args, round int, started time.Time, tracer *Tracer, machine_id string, baseline map[BaselineKey]BaselineEntry) int {
+
+	print_gomaxprocs(args.get_gomaxprocs())
+	report := start_report(ctx, args, machine_id)
+
+	if args.get_interval() > 0 {
+		report.set_round(round, started)
+	}
+
+	// Only the first round may continue an interrupted one
+	resumed := args.is_resumed() && round == 1
+	checkpoint, err := open_checkpoint(args.get_checkpoint_path(), args.format_fingerprint(), resumed)
+	exit_on_io_error(err)
+
+	if resumed {
+		print_resumed(checkpoint.count_restored(), checkpoint.get_path())
+	}
+
+	reporters := args.make_reporters(round)
+
+	for _, experiment := range args.get_experiments() {
+		err = run_experiment(ctx, &report, experiment, len(args.get_experiments()), tracer, checkpoint, reporters)
+		if err != nil || ctx.Err() != nil {
+			break
+		}
+	}
+
+	exit_code := get_measurement_exit_code(ctx, err)
+
+	if err != nil {
+		print_abort(err)
+	}
+
+	report.set_interruption(describe_interruption(ctx, err))
+
+	if report.is_partial() {
+		print_partial(&report)
+	}
+
+	if err == nil && ctx.Err() == nil {
+		checkpoint.remove()
+	} else if checkpoint != nil {
+		checkpoint.close()
+		print_checkpoint_kept(checkpoint.get_path())
+	}
+
+	if err := reporters.Flush(&report); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		exit_code = EXIT_IOError
+	} else if args.get_out_dir() != "" {
+		print_out_file_path(args.get_out_file_path())
+	}
+
+	if baseline != nil {
+		regressions, n_matched := find_regressions(&report, baseline, args.get_tolerance())
+		print_regressions(args.get_baseline_path(), regressions, n_matched)
+		if (len(regressions) > 0 || n_matched == 0) && exit_code == EXIT_Success {
+			exit_code = EXIT_Regression
+		}
+	}
+
+	return exit_code
+}
+
+// Sleeps until the next round is due, false when the run is stopped meanwhile
+func wait_next_round(ctx context.Context, next time.Time) bool {
+
+	print_next_round(next)
+
+	timer := time.NewTimer(time.Until(next))
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func create_run_context(deadline_sec int) (context.Context, context.CancelFunc) {
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	// Once the run is cancelled, another Ctrl-C kills it at once
+	context.AfterFunc(ctx, stop)
+
+	if deadline_sec > 0 {
+		deadline_ctx, cancel := context.WithTimeout(ctx, time.Duration(deadline_sec)*time.Second)
+		return deadline_ctx, func() {
+			cancel()
+			stop()
+		}
+	} else {
+		return ctx, stop
+	}
+}
+
+// Runs the command of os.Args and exits with its exit code
+func run_command_line() {
+
+	var args Args
+
+	args.parse(os.Args)
+
+	// Printing learns of --no-color the way it learns of NO_COLOR,
+	// and so does a restarted copy of the process
+	if args.is_color_off() {
+		os.Setenv("NO_COLOR", "1")
+	}
+
+	if args.get_command() != CMD_Help && args.is_preempt_off() && !is_async_preempt_off() {
+		os.Exit(restart_with_godebug("asyncpreemptoff=1", args.get_stdin()))
+	}
+
+	// A child process only reports its task to the parent,
+	// and a completion script goes straight into a shell
+	if args.get_command() != CMD_RunChildTask && args.get_command() != CMD_GenerateCompletion {
+		print_salutation()
+	}
+
+	runtime.GOMAXPROCS(args.get_gomaxprocs())
+
+	switch args.get_command() {
+	case CMD_Help:
+		if err := args.validate_command(); err != nil {
+			print_usage_error(err)
+			os.Exit(EXIT_InvalidArgs)
+		}
+		print_help()
+	case CMD_RequestSysParams:
+		if err := args.validate_command(); err != nil {
+			print_usage_error(err)
+			os.Exit(EXIT_InvalidArgs)
+		}
+		ctx, cancel := create_run_context(0)
+		defer cancel()
+		exit_on_io_error(test_sysparams(ctx, args.get_machine_path()))
+		if ctx.Err() != nil {
+			os.Exit(EXIT_Interrupted)
+		}
+	case CMD_GenerateCompletion:
+		if err := args.validate_completion(); err == nil {
+			shell, _ := args.get_shell()
+			fmt.Fprint(CONSOLE, format_completion(shell))
+		} else {
+			print_usage_error(err)
+			os.Exit(EXIT_InvalidArgs)
+		}
+	case CMD_CompareReports:
+		if err := args.validate_comparison(); err == nil {
+			paths := args.get_report_paths()
+			path_a, err := resolve_report_path(args.get_reports_dir(), paths[0], args.get_labels())
+			exit_on_io_error(err)
+			path_b, err := resolve_report_path(args.get_reports_dir(), paths[1], args.get_labels())
+			exit_on_io_error(err)
+			exit_on_io_error(compare_reports(path_a, path_b, args.get_alpha()))
+		} else {
+			print_usage_error(err)
+			os.Exit(EXIT_InvalidArgs)
+		}
+	case CMD_ListReports:
+		if err := args.validate_listing(); err == nil {
+			exit_on_io_error(list_reports(args.get_reports_dir(), args.get_labels()))
+		} else {
+			print_usage_error(err)
+			os.Exit(EXIT_InvalidArgs)
+		}
+	case CMD_RunChildTask:
+		ctx, cancel := create_run_context(0)
+		defer cancel()
+		if err := run_child_task(ctx, args.get_workloads()[0], args.get_n_cycles()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(EXIT_Failure)
+		}
+	case CMD_MeasureConcurrencyProfit, CMD_CompareExecutors:
+		if err := args.validate(); err == nil {
+			if args.is_dry_run() {
+				cycles_per_sec := measure_cycles_per_sec(context.Background(), DRY_RUN_CALIBRATION)
+				entries := []PlanEntry{}
+				for _, experiment := range args.get_experiments() {
+					entries = append(entries, plan_experiment(experiment, len(args.get_experiments()), cycles_per_sec)...)
+				}
+				print_dry_run_footer(entries)
+				return
+			}
+			if args.get_out_dir() != "" {
+				exit_on_io_error(os.MkdirAll(args.get_out_dir(), 0755))
+			}
+			machine_id := ""
+			if args.get_machine_path() != "" {
+				profile, err := load_machine_profile(args.get_machine_path())
+				exit_on_io_error(err)
+				if machine_id = profile.get_id(); machine_id != identify_machine() {
+					print_machine_mismatch(machine_id, identify_machine())
+				}
+			}
+			// A baseline that cannot be read is better found before hours of measuring
+			var baseline map[BaselineKey]BaselineEntry = nil
+			if args.get_baseline_path() != "" {
+				baseline, err = load_baseline(args.get_baseline_path())
+				exit_on_io_error(err)
+			}
+			ctx, cancel := create_run_context(args.get_deadline_sec())
+			defer cancel()
+			tracer, err := open_tracer(args.get_trace_path(), args.get_trace_tasks())
+			exit_on_io_error(err)
+			profiler, err := start_profiler(args.get_cpu_profile_path(), args.get_mem_profile_path())
+			exit_on_io_error(err)
+			exit_code := EXIT_Success
+			for round := 1; ; round++ {
+				started := time.Now()
+				if args.get_interval() > 0 {
+					if round > 1 && args.get_out_dir() != "" {
+						args.rename_out_file(started)
+					}
+					print_round_title(round, started)
+				}
+				exit_code = measure_round(ctx, args, round, started, tracer, machine_id, baseline)
+				if args.get_interval() == 0 || ctx.Err() != nil || !wait_next_round(ctx, started.Add(args.get_interval())) {
+					break
+				}
+			}
+			tracer.close()
+			if err := profiler.stop(); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				exit_code = EXIT_IOError
+			}
+			os.Exit(exit_code)
+		} else {
+			print_usage_error(err)
+			os.Exit(EXIT_InvalidArgs)
+		}
+
+	}
+}
@@ -0,0 +1,16 @@
+//go:build cgo
+
+// * * ** *** ***** ******** ************* *********************
+// Blocking inside C code
+// * * ** *** ***** ******** ************* *********************
+
+package conctest
+
+// #include <unistd.h>
+import "C"
+
+const CGO_AVAILABLE = true
+
+func block_cgo(n_cycles int) {
+	C.usleep(C.useconds_t(n_cycles))
+}
@@ -0,0 +1,263 @@
+//go:build linux
+
+// * * ** *** ***** ******** ************* *********************
+// Reading CPU time, performance counters, and the machine on Linux
+// * * ** *** ***** ******** ************* *********************
+
+package conctest
+
+import (
+	"encoding/binary"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// CPU time of threads
+
+const CLOCK_THREAD_CPUTIME_ID = 3
+const RUSAGE_THREAD = 1
+
+// CPU time the calling thread has consumed in user and system mode:
+// clock_gettime(CLOCK_THREAD_CPUTIME_ID) gives the exact total,
+// getrusage(RUSAGE_THREAD) the system part, which follows scheduler
+// ticks, and both parts where the clock is not readable
+func read_thread_cpu_time() (time.Duration, time.Duration, bool) {
+
+	var usage syscall.Rusage
+
+	if err := syscall.Getrusage(RUSAGE_THREAD, &usage); err != nil {
+		return 0, 0, false
+	}
+
+	user := time.Duration(usage.Utime.Nano())
+	system := time.Duration(usage.Stime.Nano())
+
+	var clock syscall.Timespec
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_CLOCK_GETTIME,
+		CLOCK_THREAD_CPUTIME_ID,
+		uintptr(unsafe.Pointer(&clock)),
+		0)
+
+	if errno == 0 {
+		user = max(time.Duration(clock.Nano())-system, 0)
+	}
+
+	return user, system, true
+}
+
+// Voluntary and involuntary context switches of all threads of the process so far
+func read_context_switches() (int64, int64, bool) {
+
+	var usage syscall.Rusage
+
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, 0, false
+	} else {
+		return int64(usage.Nvcsw), int64(usage.Nivcsw), true
+	}
+}
+
+// Describing the machine
+
+// The model and the current frequency of the first CPU, the frequency
+// is 0 where the kernel does not tell it, as on most ARM boards
+func read_cpu_model() (string, float64) {
+
+	content, err := os.ReadFile("/proc/cpuinfo")
+
+	if err != nil {
+		return "", 0
+	}
+
+	model := ""
+	mhz := 0.0
+
+	for _, line := range strings.Split(string(content), "\n") {
+		name, value, _ := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		value = strings.TrimSpace(value)
+		if (name == "model name" || name == "Model" || name == "Hardware") && model == "" {
+			model = value
+		} else if name == "cpu MHz" && mhz == 0 {
+			mhz, _ = strconv.ParseFloat(value, 64)
+		}
+	}
+
+	return model, mhz
+}
+
+func read_memory_bytes() int64 {
+
+	content, err := os.ReadFile("/proc/meminfo")
+
+	if err != nil {
+		return 0
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kb, _ := strconv.ParseInt(fields[1], 10, 64)
+			return kb * 1024
+		}
+	}
+
+	return 0
+}
+
+func read_os_release() string {
+	content, _ := os.ReadFile("/proc/sys/kernel/osrelease")
+	return strings.TrimSpace(string(content))
+}
+
+// Performance counters
+
+const PERF_TYPE_HARDWARE = 0
+const PERF_TYPE_SOFTWARE = 1
+
+const PERF_COUNT_HW_CPU_CYCLES = 0
+const PERF_COUNT_HW_INSTRUCTIONS = 1
+const PERF_COUNT_HW_CACHE_MISSES = 3
+const PERF_COUNT_SW_CONTEXT_SWITCHES = 3
+
+const PERF_FORMAT_TOTAL_TIME_ENABLED = 1 << 0
+const PERF_FORMAT_TOTAL_TIME_RUNNING = 1 << 1
+
+const PERF_ATTR_INHERIT = 1 << 1
+const PERF_ATTR_EXCLUDE_KERNEL = 1 << 5
+const PERF_ATTR_EXCLUDE_HV = 1 << 6
+
+const PERF_FLAG_FD_CLOEXEC = 1 << 3
+
+// The first version of struct perf_event_attr, which every kernel accepts
+type PerfEventAttr struct {
+	event_type    uint32
+	size          uint32
+	config        uint64
+	sample_period uint64
+	sample_type   uint64
+	read_format   uint64
+	flags         uint64
+	wakeup_events uint32
+	bp_type       uint32
+	config1       uint64
+}
+
+type PerfEvent struct {
+	event_type uint32
+	config     uint64
+}
+
+// In the order of the fields of PerfCounts
+var PERF_EVENTS = []PerfEvent{
+	{PERF_TYPE_HARDWARE, PERF_COUNT_HW_INSTRUCTIONS},
+	{PERF_TYPE_HARDWARE, PERF_COUNT_HW_CPU_CYCLES},
+	{PERF_TYPE_HARDWARE, PERF_COUNT_HW_CACHE_MISSES},
+	{PERF_TYPE_SOFTWARE, PERF_COUNT_SW_CONTEXT_SWITCHES},
+}
+
+// Counters of every thread of the process, a list per event; threads
+// started later are counted with the threads that start them
+type PerfCounters struct {
+	fds [][]int
+}
+
+// Counts in the kernel too where perf_event_paranoid allows it,
+// in user space only otherwise
+func open_perf_event(event PerfEvent, tid int) (int, error) {
+
+	attr := PerfEventAttr{
+		event.event_type,
+		uint32(unsafe.Sizeof(PerfEventAttr{})),
+		event.config,
+		0,
+		0,
+		PERF_FORMAT_TOTAL_TIME_ENABLED | PERF_FORMAT_TOTAL_TIME_RUNNING,
+		PERF_ATTR_INHERIT | PERF_ATTR_EXCLUDE_HV,
+		0,
+		0,
+		0}
+
+	var errno syscall.Errno
+
+	for _, exclude_kernel := range []uint64{0, PERF_ATTR_EXCLUDE_KERNEL} {
+		attr.flags |= exclude_kernel
+		var fd uintptr
+		fd, _, errno = syscall.Syscall6(
+			syscall.SYS_PERF_EVENT_OPEN,
+			uintptr(unsafe.Pointer(&attr)),
+			uintptr(tid),
+			^uintptr(0),
+			^uintptr(0),
+			PERF_FLAG_FD_CLOEXEC,
+			0)
+		if errno == 0 {
+			return int(fd), nil
+		}
+	}
+
+	return -1, errno
+}
+
+// Returns nil where no event may be counted, as without
+// the rights perf_event_paranoid asks for or in most containers
+func open_perf_counters() *PerfCounters {
+
+	task_dirs, err := os.ReadDir("/proc/self/task")
+
+	if err != nil {
+		return nil
+	}
+
+	counters := PerfCounters{make([][]int, len(PERF_EVENTS))}
+	n_open := 0
+
+	for event_idx, event := range PERF_EVENTS {
+		for _, task_dir := range task_dirs {
+			if tid, err := strconv.Atoi(task_dir.Name()); err == nil {
+				if fd, err := open_perf_event(event, tid); err == nil {
+					counters.fds[event_idx] = append(counters.fds[event_idx], fd)
+					n_open++
+				}
+			}
+		}
+	}
+
+	if n_open > 0 {
+		return &counters
+	} else {
+		return nil
+	}
+}
+
+// Sums the counts of all threads, scaled up for the time the kernel had
+// the counters switched out, and closes the counters
+func (c *PerfCounters) close_and_read() PerfCounts {
+
+	counts := make([]int64, len(PERF_EVENTS))
+	record := make([]byte, 24)
+
+	for event_idx, fds := range c.fds {
+
+		counts[event_idx] = -1
+
+		for _, fd := range fds {
+			if n, err := syscall.Read(fd, record); err == nil && n == len(record) {
+				value := float64(binary.NativeEndian.Uint64(record[0:]))
+				enabled := float64(binary.NativeEndian.Uint64(record[8:]))
+				running := float64(binary.NativeEndian.Uint64(record[16:]))
+				if running > 0 {
+					value *= enabled / running
+				}
+				counts[event_idx] = max(counts[event_idx], 0) + int64(value)
+			}
+			syscall.Close(fd)
+		}
+	}
+
+	return PerfCounts{counts[0], counts[1], counts[2], counts[3]}
+}
@@ -0,0 +1,13 @@
+//go:build !cgo
+
+// * * ** *** ***** ******** ************* *********************
+// Blocking inside C code is not available without cgo
+// * * ** *** ***** ******** ************* *********************
+
+package conctest
+
+const CGO_AVAILABLE = false
+
+func block_cgo(n_cycles int) {
+	panic("cgo is not available in this build")
+}
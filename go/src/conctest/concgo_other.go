@@ -0,0 +1,39 @@
+//go:build !linux
+
+// * * ** *** ***** ******** ************* *********************
+// CPU time, performance counters, and the machine are not readable outside Linux
+// * * ** *** ***** ******** ************* *********************
+
+package conctest
+
+import "time"
+
+func read_thread_cpu_time() (time.Duration, time.Duration, bool) {
+	return 0, 0, false
+}
+
+func read_context_switches() (int64, int64, bool) {
+	return 0, 0, false
+}
+
+func read_cpu_model() (string, float64) {
+	return "", 0
+}
+
+func read_memory_bytes() int64 {
+	return 0
+}
+
+func read_os_release() string {
+	return ""
+}
+
+type PerfCounters struct{}
+
+func open_perf_counters() *PerfCounters {
+	return nil
+}
+
+func (c *PerfCounters) close_and_read() PerfCounts {
+	return create_perf_counts()
+}
@@ -0,0 +1,343 @@
+package conctest
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// Testing task statistics
+
+type StatsCase struct {
+	name      string
+	durations []time.Duration
+	statuses  []TaskStatus
+	variance  float64
+}
+
+// Tasks left pending are those past the durations given
+func create_observation_of(durations []time.Duration, statuses []TaskStatus, n_tasks int, streamed bool) Observation {
+
+	var obs Observation
+
+	if streamed {
+		obs = create_streamed_observation("float", "batch", n_tasks)
+	} else {
+		obs = create_observation("float", "batch", n_tasks)
+	}
+
+	for idx, duration := range durations {
+		task := create_task(idx, 1, 0, duration, nil)
+		if statuses != nil {
+			task.set_status(statuses[idx])
+		}
+		obs.register_task(task)
+	}
+
+	return obs
+}
+
+func is_close(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(b))
+}
+
+func ms(values ...float64) []time.Duration {
+
+	durations := []time.Duration{}
+
+	for _, value := range values {
+		durations = append(durations, time.Duration(value*float64(time.Millisecond)))
+	}
+
+	return durations
+}
+
+func TestVariance(t *testing.T) {
+
+	cases := []StatsCase{
+		{"empty", ms(), nil, 0},
+		{"one sample", ms(7), nil, 0},
+		{"equal samples", ms(3, 3, 3, 3), nil, 0},
+		{"two samples", ms(1, 3), nil, 2},
+		{"known variance", ms(2, 4, 4, 4, 5, 5, 7, 9), nil, 32.0 / 7.0},
+		{"no integer truncation", ms(1, 2), nil, 0.5},
+		{"fractions of a millisecond", ms(0.25, 0.5, 0.75), nil, 0.0625},
+		{"timed out left out", ms(1, 2, 3, 1000), []TaskStatus{TS_Done, TS_Done, TS_Done, TS_TimedOut}, 1},
+		{"failed and cancelled left out", ms(1, 2, 3, 0.1, 0.2), []TaskStatus{TS_Done, TS_Done, TS_Done, TS_Failed, TS_Cancelled}, 1},
+		{"one done among timed out", ms(5, 1000), []TaskStatus{TS_Done, TS_TimedOut}, 0},
+	}
+
+	for _, c := range cases {
+		for _, streamed := range []bool{false, true} {
+
+			// Two more tasks than given stay pending, which statistics leave out as well
+			obs := create_observation_of(c.durations, c.statuses, len(c.durations)+2, streamed)
+
+			if variance := obs.get_variance(); !is_close(variance, c.variance) {
+				t.Errorf("%s, streamed %t: variance %g, expected %g", c.name, streamed, variance, c.variance)
+			}
+
+			if deviation := obs.get_standard_deviation(); !is_close(deviation, math.Sqrt(c.variance)) {
+				t.Errorf("%s, streamed %t: std. dev. %g, expected %g", c.name, streamed, deviation, math.Sqrt(c.variance))
+			}
+		}
+	}
+}
+
+func TestMeanTaskDuration(t *testing.T) {
+
+	obs := create_observation_of(ms(1, 2, 1000), []TaskStatus{TS_Done, TS_Done, TS_TimedOut}, 3, false)
+
+	if mean := obs.get_mean_task_duration(); mean != 1500*time.Microsecond {
+		t.Errorf("mean task duration %v, expected 1.5ms", mean)
+	}
+}
+
+// Testing task registration, which is meant to run with go test -race
+
+func TestObserveRegistersEveryTask(t *testing.T) {
+
+	const n_tasks = 6
+
+	executors := []string{"batch", "pool", "semaphore", "open", "ramp", "overlap", "graph", "chunked"}
+
+	for _, executor := range executors {
+		for _, streaming := range []string{"false", "true"} {
+
+			args := create_args("profit", map[string]string{
+				"tasks":     strconv.Itoa(n_tasks),
+				"cycles":    "1e4",
+				"series":    "3",
+				"executor":  executor,
+				"histogram": "log",
+				"streaming": streaming,
+			})
+
+			if err := args.validate(); err != nil {
+				t.Fatalf("%s: %v", executor, err)
+			}
+
+			setup := args.get_setups([]int{args.get_n_cycles()})[0]
+			obs := observe(context.Background(), n_tasks, setup)
+
+			if n_done := obs.count_tasks_with_status(TS_Done); obs.count_tasks() != n_tasks || n_done != n_tasks {
+				t.Errorf("%s, streaming %s: %d tasks, %d done, expected %d", executor, streaming, obs.count_tasks(), n_done, n_tasks)
+			}
+
+			n_recorded := int64(0)
+
+			for bucket_idx := 0; bucket_idx < obs.get_histogram().count_buckets(); bucket_idx++ {
+				n_recorded += obs.get_histogram().get_bucket_count(bucket_idx)
+			}
+
+			if n_recorded != n_tasks {
+				t.Errorf("%s, streaming %s: %d durations in the histogram, expected %d", executor, streaming, n_recorded, n_tasks)
+			}
+		}
+	}
+}
+
+// Testing the t-test
+
+func TestIncompleteBeta(t *testing.T) {
+
+	cases := []struct {
+		x, a, b  float64
+		expected float64
+	}{
+		{0, 2, 3, 0},
+		{1, 2, 3, 1},
+		{0.3, 1, 1, 0.3},
+		{0.5, 7, 7, 0.5},
+		{0.2, 1, 4, 1 - math.Pow(0.8, 4)},
+		{0.7, 3, 1, math.Pow(0.7, 3)},
+		{0.9, 1, 0.5, 1 - math.Sqrt(0.1)},
+	}
+
+	for _, c := range cases {
+		if value := incomplete_beta(c.x, c.a, c.b); math.Abs(value-c.expected) > 1e-10 {
+			t.Errorf("I_%g(%g, %g) = %.12f, expected %.12f", c.x, c.a, c.b, value, c.expected)
+		}
+	}
+}
+
+// Two-sided p-values at the critical values of Student's t tables
+func TestStudentTPValue(t *testing.T) {
+
+	cases := []struct {
+		t, degrees float64
+		p          float64
+	}{
+		{0, 5, 1},
+		{12.706, 1, 0.05},
+		{4.303, 2, 0.05},
+		{2.228, 10, 0.05},
+		{2.086, 20, 0.05},
+		{1.812, 10, 0.1},
+		{3.169, 10, 0.01},
+		{2.845, 20, 0.01},
+		{-2.228, 10, 0.05},
+	}
+
+	for _, c := range cases {
+		if p := student_t_p_value(c.t, c.degrees); math.Abs(p-c.p) > 2e-4 {
+			t.Errorf("t %g with %g degrees: p %.5f, expected %g", c.t, c.degrees, p, c.p)
+		}
+	}
+}
+
+// The three examples of Welch's t-test in Wikipedia, given to two
+// decimals of t, one of the degrees of freedom, and three of p,
+// so each is checked to within its last digit
+func TestWelchTTest(t *testing.T) {
+
+	cases := []struct {
+		name       string
+		a, b       []float64
+		t, degrees float64
+		p          float64
+	}{
+		{
+			"equal variances and sizes",
+			[]float64{27.5, 21.0, 19.0, 23.6, 17.0, 17.9, 16.9, 20.1, 21.9, 22.6, 23.1, 19.6, 19.0, 21.7, 21.4},
+			[]float64{27.1, 22.0, 20.8, 23.4, 23.4, 23.5, 25.8, 22.0, 24.8, 20.2, 21.9, 22.1, 22.9, 20.5, 24.4},
+			-2.46, 25.0, 0.021,
+		},
+		{
+			"unequal variances and sizes",
+			[]float64{17.2, 20.9, 22.6, 18.1, 21.7, 21.4, 23.5, 24.2, 14.7, 21.8},
+			[]float64{21.5, 22.8, 21.0, 23.0, 21.6, 23.6, 22.5, 20.7, 23.4, 21.8, 20.7, 21.7, 21.5, 22.5, 23.6, 21.5, 22.5, 23.5, 21.5, 21.8},
+			-1.57, 9.9, 0.149,
+		},
+		{
+			"unequal variances, the larger one with more values",
+			[]float64{19.8, 20.4, 19.6, 17.8, 18.5, 18.9, 18.3, 18.9, 19.5, 22.0},
+			[]float64{28.2, 26.6, 20.1, 23.3, 25.2, 22.1, 17.7, 27.6, 20.6, 13.7, 23.2, 17.5, 20.6, 18.0, 23.9, 21.6, 24.3, 20.4, 23.9, 13.3},
+			-2.22, 24.5, 0.036,
+		},
+	}
+
+	for _, c := range cases {
+
+		value, degrees, p := welch_t_test(c.a, c.b)
+
+		if math.Abs(value-c.t) > 0.01 || math.Abs(degrees-c.degrees) > 0.1 || math.Abs(p-c.p) > 0.001 {
+			t.Errorf("%s: t %.3f, degrees %.2f, p %.4f, expected %g, %g, %g", c.name, value, degrees, p, c.t, c.degrees, c.p)
+		}
+
+		// Swapping the samples flips the sign of t only
+		if swapped, _, swapped_p := welch_t_test(c.b, c.a); !is_close(swapped, -value) || !is_close(swapped_p, p) {
+			t.Errorf("%s: swapped t %g, p %g, expected %g, %g", c.name, swapped, swapped_p, -value, p)
+		}
+	}
+}
+
+func TestWelchTTestDegenerate(t *testing.T) {
+
+	cases := []struct {
+		name string
+		a, b []float64
+		t, p float64
+	}{
+		{"equal samples", []float64{1, 2, 3, 4}, []float64{1, 2, 3, 4}, 0, 1},
+		{"equal constants", []float64{5, 5, 5}, []float64{5, 5}, 0, 1},
+		{"different constants", []float64{5, 5, 5}, []float64{6, 6}, math.Inf(-1), 0},
+		{"a single value", []float64{5}, []float64{6, 7, 8}, 0, 1},
+		{"no values", []float64{}, []float64{6, 7}, 0, 1},
+	}
+
+	for _, c := range cases {
+		if value, _, p := welch_t_test(c.a, c.b); value != c.t || p != c.p {
+			t.Errorf("%s: t %g, p %g, expected %g, %g", c.name, value, p, c.t, c.p)
+		}
+	}
+}
+
+// Testing the scalability models
+
+func usl_points(contention, coherence float64, task_counts ...int) []ScalePoint {
+
+	points := []ScalePoint{}
+
+	for _, n_tasks := range task_counts {
+		n := float64(n_tasks)
+		points = append(points, ScalePoint{n_tasks, n / (1 + contention*(n-1) + coherence*n*(n-1))})
+	}
+
+	return points
+}
+
+func amdahl_points(serial_fraction float64, task_counts ...int) []ScalePoint {
+
+	points := []ScalePoint{}
+
+	for _, n_tasks := range task_counts {
+		points = append(points, ScalePoint{n_tasks, 1 / (serial_fraction + (1-serial_fraction)/float64(n_tasks))})
+	}
+
+	return points
+}
+
+func TestFitUSL(t *testing.T) {
+
+	cases := []struct {
+		name       string
+		points     []ScalePoint
+		contention float64
+		coherence  float64
+		optimum    float64
+	}{
+		{"contention and coherence", usl_points(0.05, 0.001, 1, 2, 4, 8, 16, 32), 0.05, 0.001, math.Sqrt(0.95 / 0.001)},
+		{"coherence only", usl_points(0, 0.01, 1, 2, 3, 4, 6, 8), 0, 0.01, 10},
+		{"contention only", usl_points(0.2, 0, 1, 2, 4, 8), 0.2, 0, math.Inf(1)},
+		{"linear speedup", usl_points(0, 0, 1, 2, 4, 8), 0, 0, math.Inf(1)},
+		{"flat speedup", usl_points(1, 0, 1, 2, 4, 8), 1, 0, 1},
+		{"slowing down", []ScalePoint{{1, 1}, {2, 0.5}, {4, 0.25}}, 1, 1, 1},
+		{"superlinear speedup", []ScalePoint{{1, 1}, {2, 2.5}, {4, 6}}, 0, 0, math.Inf(1)},
+		{"a single task count", []ScalePoint{{4, 2}}, 1.0 / 3.0, 0, math.Inf(1)},
+		{"one task only", []ScalePoint{{1, 1}}, 0, 0, math.Inf(1)},
+	}
+
+	for _, c := range cases {
+
+		contention, coherence := fit_usl(c.points)
+
+		if !is_close(contention, c.contention) || !is_close(coherence, c.coherence) {
+			t.Errorf("%s: contention %g, coherence %g, expected %g, %g", c.name, contention, coherence, c.contention, c.coherence)
+		}
+
+		if contention < 0 || contention > 1 || coherence < 0 {
+			t.Errorf("%s: contention %g, coherence %g out of range", c.name, contention, coherence)
+		}
+
+		if optimum := find_usl_optimum(contention, coherence); !(optimum == c.optimum || is_close(optimum, c.optimum)) {
+			t.Errorf("%s: optimum %g, expected %g", c.name, optimum, c.optimum)
+		}
+	}
+}
+
+func TestFitAmdahl(t *testing.T) {
+
+	cases := []struct {
+		name            string
+		points          []ScalePoint
+		serial_fraction float64
+	}{
+		{"a tenth serial", amdahl_points(0.1, 1, 2, 4, 8, 16), 0.1},
+		{"half serial", amdahl_points(0.5, 1, 2, 3, 4), 0.5},
+		{"linear speedup", amdahl_points(0, 1, 2, 4, 8), 0},
+		{"flat speedup", amdahl_points(1, 1, 2, 4, 8), 1},
+		{"slowing down", []ScalePoint{{1, 1}, {2, 0.5}, {4, 0.25}}, 1},
+		{"superlinear speedup", []ScalePoint{{1, 1}, {2, 2.5}, {4, 6}}, 0},
+		{"a single task count", []ScalePoint{{4, 2}}, 1.0 / 3.0},
+		{"one task only", []ScalePoint{{1, 1}}, 0},
+	}
+
+	for _, c := range cases {
+		if serial_fraction := fit_amdahl(c.points); !is_close(serial_fraction, c.serial_fraction) {
+			t.Errorf("%s: serial fraction %g, expected %g", c.name, serial_fraction, c.serial_fraction)
+		}
+	}
+}
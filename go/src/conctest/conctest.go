@@ -0,0 +1,354 @@
+// * * ** *** ***** ******** ************* *********************
+// Measuring from other programs
+// * * ** *** ***** ******** ************* *********************
+
+// Package conctest measures what running tasks concurrently gains or costs
+// compared with running them one after another. The conctest command is
+// a thin wrapper around Main; other programs, like test harnesses of their
+// own, call Measure and read the report it gives back.
+package conctest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Main runs the conctest command with the arguments of the process
+// and exits with its exit code
+func Main() {
+	run_command_line()
+}
+
+// Every operation that runs for long takes a context and stops soon
+// after it is cancelled, giving back ctx.Err()
+
+// SetOutput sends the console messages of the measurements to w instead
+// of stdout; it is not to be called while a measurement runs
+func SetOutput(w io.Writer) {
+	CONSOLE = w
+}
+
+// Measure sweeps the task counts as the profit or the executors command
+// does, in the calling process, with options named as on the command line
+// without the dashes, like map[string]string{"tasks": "8", "cycles": "1e6"}.
+// The console output is the same as with the command, the report is given
+// back instead of saved, and the reporters hear of the observations
+// as they are made. A measurement cut short by ctx, a deadline, or
+// a failed task gives back what was observed along with the error.
+func Measure(ctx context.Context, command string, options map[string]string, reporters ...Reporter) (*Report, error) {
+
+	args := create_args(command, options)
+
+	if err := args.validate_embedded(); err != nil {
+		return nil, err
+	}
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(args.get_gomaxprocs()))
+
+	if args.get_deadline_sec() > 0 {
+		deadline_ctx, cancel := context.WithTimeout(ctx, time.Duration(args.get_deadline_sec())*time.Second)
+		defer cancel()
+		ctx = deadline_ctx
+	}
+
+	report := start_report(ctx, args, "")
+	all_reporters := append(Reporters{create_console_reporter(args.is_explained())}, reporters...)
+
+	var err error = nil
+
+	for _, experiment := range args.get_experiments() {
+		err = run_experiment(ctx, &report, experiment, len(args.get_experiments()), nil, nil, all_reporters)
+		if err != nil || ctx.Err() != nil {
+			break
+		}
+	}
+
+	report.set_interruption(describe_interruption(ctx, err))
+
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+
+	return &report, errors.Join(err, all_reporters.Flush(&report))
+}
+
+// Calibrate gives the number of cycles of the float workload that takes
+// the task duration on this machine, as the task-ms option calibrates them
+func Calibrate(ctx context.Context, task_duration time.Duration) (int, error) {
+
+	n_cycles := calibrate_n_cycles(ctx, task_duration)
+
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	} else {
+		return n_cycles, nil
+	}
+}
+
+// Observe runs n_tasks tasks once as the first setup the options describe,
+// without the baseline of one task that profits and costs compare with
+func Observe(ctx context.Context, n_tasks int, options map[string]string) (Observation, error) {
+
+	options = maps.Clone(options)
+	options["tasks"] = strconv.Itoa(n_tasks)
+	args := create_args("profit", options)
+
+	if err := args.validate_embedded(); err != nil {
+		return Observation{}, err
+	}
+
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(args.get_gomaxprocs()))
+
+	n_cycles := args.get_cycle_counts()[0]
+
+	if args.get_task_ms() > 0 {
+		if n_cycles = calibrate_n_cycles(ctx, from_ms(args.get_task_ms())); ctx.Err() != nil {
+			return Observation{}, ctx.Err()
+		}
+	}
+
+	obs := observe(ctx, n_tasks, args.get_setups([]int{n_cycles})[0])
+
+	if first_failure := obs.get_first_failure(); first_failure != nil {
+		return obs, first_failure
+	} else {
+		return obs, ctx.Err()
+	}
+}
+
+// Options that take the command line process, which a measurement
+// run by another program cannot have
+func (a Args) validate_embedded() error {
+
+	if a.get_command() != CMD_MeasureConcurrencyProfit && a.get_command() != CMD_CompareExecutors {
+		return fmt.Errorf("unknown command %q, expected profit or executors", a.command_name)
+	}
+
+	if err := a.validate(); err != nil {
+		return err
+	}
+
+	if a.is_preempt_off() && !is_async_preempt_off() {
+		return errors.New("--preempt off takes GODEBUG=asyncpreemptoff=1 in the calling process")
+	} else if slices.Contains(a.get_executors(), EX_Process) {
+		return errors.New("the process executor runs tasks in copies of the conctest command")
+	} else {
+		return nil
+	}
+}
+
+// Workloads gives the workloads the options name, like
+// map[string]string{"workload": "map", "keys": "1000"}, ready to run
+func Workloads(options map[string]string) ([]Workload, error) {
+
+	args := create_args("profit", options)
+
+	if args.options_err != nil {
+		return nil, args.options_err
+	} else if !args.workload_valid {
+		return nil, errors.New("--workload must name workloads among " + strings.Join(WORKLOAD_NAMES, ", ") + args.format_given("workload"))
+	}
+
+	workloads := []Workload{}
+
+	for _, workload := range args.get_workloads() {
+		workloads = append(workloads, workload.prepare())
+	}
+
+	return workloads, nil
+}
+
+func (w Workload) Name() string {
+	return w.get_name()
+}
+
+// Run does the work of a task of n_cycles cycles
+func (w Workload) Run(ctx context.Context, n_cycles int) error {
+	_, err := w.run(ctx, n_cycles)
+	return err
+}
+
+// What a Reporter hears an event of comes with the setup of the
+// observation, telling the workload, executor, cycles, and series size
+func (s Setup) WorkloadName() string {
+	return s.get_workload().get_name()
+}
+
+func (s Setup) ExecutorName() string {
+	return s.get_executor_name()
+}
+
+// Cycles of a task as set up, before the sizing distribution applies
+func (s Setup) Cycles() int {
+	return s.get_n_cycles()
+}
+
+func (s Setup) SeriesSize() int {
+	return s.get_series_size()
+}
+
+// Reps is how many times each observation is repeated
+func (s Setup) Reps() int {
+	return s.count_reps()
+}
+
+// Observations of the report as copies, changing them leaves the report as it is
+func (r *Report) Observations() []Observation {
+
+	observations := []Observation{}
+
+	for _, obs := range r.observations {
+		observations = append(observations, obs.clone())
+	}
+
+	return observations
+}
+
+// Seed repeats the random draws of the measurement with the seed option
+func (r *Report) Seed() int64 {
+	return r.get_seed()
+}
+
+// WriteCSV writes the report the way the command saves it
+func (r *Report) WriteCSV(w io.Writer) error {
+	return write_report(w, r, false)
+}
+
+func (r *Report) Save(path string) error {
+	return save_file(path, r.WriteCSV)
+}
+
+func (o Observation) WorkloadName() string {
+	return o.get_workload_name()
+}
+
+func (o Observation) ExecutorName() string {
+	return o.get_executor_name()
+}
+
+func (o Observation) Tasks() int {
+	return o.count_tasks()
+}
+
+func (o Observation) TotalDuration() time.Duration {
+	return o.get_total_duration()
+}
+
+func (o Observation) MeanTaskDuration() time.Duration {
+	return o.get_mean_task_duration()
+}
+
+// Cost is the share of the serial time the tasks lost to running concurrently
+func (o Observation) Cost() float64 {
+	return o.get_concurrency_cost()
+}
+
+// Profit is the share of the serial time concurrency saved
+func (o Observation) Profit() float64 {
+	return o.get_concurrency_profit()
+}
+
+func (o Observation) Speedup() float64 {
+	return o.get_speedup()
+}
+
+func (o Observation) Efficiency() float64 {
+	return o.get_efficiency()
+}
+
+// Hooks calls back a program measuring with Measure as the observations
+// of the report are made, for aggregation or dashboards of its own; it is
+// a Reporter, any of its functions may be nil. OnTask comes from the
+// goroutines of the tasks, at the same time for tasks running together,
+// OnSeries comes for each series of an observation before OnObservation;
+// both get a copy of the observation, not the one in the report
+type Hooks struct {
+	OnTask        func(task Task)
+	OnSeries      func(obs *Observation, series Series)
+	OnObservation func(obs *Observation)
+}
+
+func (h Hooks) OnObservationStart(setup Setup, n_tasks int) {}
+
+func (h Hooks) OnTaskDone(setup Setup, task Task) {
+	if h.OnTask != nil {
+		h.OnTask(task)
+	}
+}
+
+func (h Hooks) OnObservationDone(setup Setup, report *Report, obs *Observation) {
+
+	copied := obs.clone()
+
+	if h.OnSeries != nil {
+		for _, series := range copied.Series() {
+			h.OnSeries(&copied, series)
+		}
+	}
+
+	if h.OnObservation != nil {
+		h.OnObservation(&copied)
+	}
+}
+
+func (h Hooks) Flush(report *Report) error {
+	return nil
+}
+
+// Series tells how the tasks launched together went, with times
+// counted from the start of the observation
+type Series struct {
+	Index    int
+	Started  time.Duration
+	Finished time.Duration
+	IdleTail time.Duration
+}
+
+// Series of the observation in the order of launching, none for
+// a streamed observation, which keeps no tasks
+func (o Observation) Series() []Series {
+
+	series := []Series{}
+
+	for series_idx := 0; series_idx < o.count_series(); series_idx++ {
+		started, finished := o.get_series_window(series_idx)
+		series = append(series, Series{series_idx, started, finished, o.get_series_idle_tail(series_idx)})
+	}
+
+	return series
+}
+
+func (t Task) Index() int {
+	return t.get_idx()
+}
+
+func (t Task) Cycles() int {
+	return t.get_n_cycles()
+}
+
+// Started counts from the start of the observation: in OnTask from
+// the moment the tasks begin to launch, in a report from the first launch
+func (t Task) Started() time.Duration {
+	return t.get_start()
+}
+
+func (t Task) Duration() time.Duration {
+	return t.get_duration()
+}
+
+// Status is done, failed, cancelled, or timed out, as in the report
+func (t Task) Status() string {
+	return format_task_status(t.get_status())
+}
+
+func (t Task) Err() error {
+	return t.get_err()
+}